@@ -0,0 +1,174 @@
+package todo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/leep-frog/command"
+	"github.com/leep-frog/command/color"
+)
+
+const (
+	sinceFlag = "since"
+	untilFlag = "until"
+)
+
+var (
+	// pastSuggestions are the values suggested when completing the start or
+	// end of a date range.
+	pastSuggestions = []string{"today", "yesterday", "mon", "tue", "wed", "thu", "fri", "sat", "sun", "7d", "2w"}
+)
+
+// ArchivedItem is an item that was removed from the list by archiving it.
+type ArchivedItem struct {
+	// Path is where the item was in the list when it was archived.
+	Path []string
+	// Item is the archived item, including its sub-items.
+	Item *Item
+	// Format is the item's format if it was a primary item.
+	Format *color.Format `json:",omitempty"`
+	// Archived is when the item was archived.
+	Archived time.Time
+}
+
+// ArchiveItem moves an item (and its sub-items) from the list into the archive.
+func (tl *List) ArchiveItem(output command.Output, data *command.Data) error {
+	path := data.StringList(pathArg)
+	item, err := tl.get(path)
+	if err != nil {
+		return output.Stderrf("%v\n", err)
+	}
+
+	ai := &ArchivedItem{
+		Path:     append([]string{}, path...),
+		Item:     item,
+		Archived: now(),
+	}
+	tl.releaseBlockers(output, path)
+	parent, name := path[:len(path)-1], path[len(path)-1]
+	delete(tl.children(parent), name)
+	tl.removeOrder(parent, name)
+	if len(path) == 1 {
+		ai.Format = tl.PrimaryFormats[name]
+		delete(tl.PrimaryFormats, name)
+	}
+	tl.Archive = append(tl.Archive, ai)
+	tl.logEvent(eventArchived, path)
+	tl.changed = true
+	return nil
+}
+
+// archiveRange returns the range of archive times selected by the since and
+// until flags. The until date is inclusive.
+func archiveRange(data *command.Data) (time.Time, time.Time, error) {
+	var since, until time.Time
+	if data.Has(sinceFlag) {
+		var err error
+		if since, err = parsePast(data.String(sinceFlag), now()); err != nil {
+			return since, until, err
+		}
+	}
+	if data.Has(untilFlag) {
+		var err error
+		if until, err = parsePast(data.String(untilFlag), now()); err != nil {
+			return since, until, err
+		}
+		until = until.AddDate(0, 0, 1)
+	}
+	return since, until, nil
+}
+
+// ListArchive outputs the archived items, optionally limited to those
+// archived within a date range.
+func (tl *List) ListArchive(output command.Output, data *command.Data) error {
+	since, until, err := archiveRange(data)
+	if err != nil {
+		return output.Stderrf("%v\n", err)
+	}
+
+	for _, ai := range tl.Archive {
+		if ai.Archived.Before(since) || (!until.IsZero() && !ai.Archived.Before(until)) {
+			continue
+		}
+		box := openBox
+		if ai.Item.Done {
+			box = doneBox
+		}
+		output.Stdoutln(fmt.Sprintf("%s %s %s%s", ai.Archived.Format(timeFormat), box, strings.Join(ai.Path, " > "), itemSuffix(ai.Item)))
+		listItems(output, ai.Item, 1, &listOptions{})
+	}
+	return nil
+}
+
+// RestoreItem moves the most recently archived item with the provided path
+// back into the list. Any items in its path that no longer exist are created.
+func (tl *List) RestoreItem(output command.Output, data *command.Data) error {
+	path := data.StringList(pathArg)
+	idx := -1
+	for i, ai := range tl.Archive {
+		if len(ai.Path) == len(path) && isPrefix(path, ai.Path) {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return output.Stderrf("item %s is not archived\n", pathString(path))
+	}
+	if _, err := tl.get(path); err == nil {
+		return output.Stderrf("item %s already exists\n", pathString(path))
+	}
+
+	ai := tl.Archive[idx]
+	parent, name := path[:len(path)-1], path[len(path)-1]
+	if len(parent) > 0 {
+		tl.mergeItems([]*importedItem{{path: parent}})
+	}
+	if tl.Items == nil {
+		tl.Items = map[string]*Item{}
+	}
+	if len(parent) == 0 {
+		tl.Items[name] = ai.Item
+	} else {
+		p, _ := tl.get(parent)
+		if p.Items == nil {
+			p.Items = map[string]*Item{}
+		}
+		p.Items[name] = ai.Item
+	}
+	tl.appendOrder(parent, name)
+	if len(path) == 1 && ai.Format != nil {
+		if tl.PrimaryFormats == nil {
+			tl.PrimaryFormats = map[string]*color.Format{}
+		}
+		tl.PrimaryFormats[name] = ai.Format
+	}
+
+	tl.Archive = append(tl.Archive[:idx], tl.Archive[idx+1:]...)
+	tl.changed = true
+	return nil
+}
+
+// archiveCompleter suggests the next element of the paths of archived items.
+func archiveCompleter(l *List) command.Completer[[]string] {
+	return command.CompleterFromFunc(func(path []string, data *command.Data) (*command.Completion, error) {
+		var prefix []string
+		if len(path) > 0 {
+			prefix = path[:len(path)-1]
+		}
+
+		seen := map[string]bool{}
+		var suggestions []string
+		for _, ai := range l.Archive {
+			if len(ai.Path) <= len(prefix) || !isPrefix(prefix, ai.Path) {
+				continue
+			}
+			if s := ai.Path[len(prefix)]; !seen[s] {
+				seen[s] = true
+				suggestions = append(suggestions, s)
+			}
+		}
+		return &command.Completion{
+			Suggestions: suggestions,
+		}, nil
+	})
+}
@@ -0,0 +1,241 @@
+package todo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/leep-frog/command"
+)
+
+const (
+	onFlag = "on"
+
+	// faintStart and faintEnd are the ANSI escape codes that display text
+	// faintly, which the color package has no attribute for. faintEnd only
+	// resets the intensity, so faint text keeps its color.
+	faintStart = "\033[2m"
+	faintEnd   = "\033[22m"
+)
+
+// faint returns s formatted to be displayed faintly.
+func faint(s string) string {
+	if s == "" {
+		return ""
+	}
+	return faintStart + s + faintEnd
+}
+
+// blockedLine returns the line displayed for an item that is blocked by open
+// items. Each part of the line is dimmed separately so that a reset at the end
+// of a highlighted part (such as an overdue date) doesn't end the dimming.
+func blockedLine(box, name string, item *Item, blocked string) string {
+	line := faint(box + " " + name)
+	for _, s := range suffixes(item) {
+		line += faint(s)
+	}
+	return line + faint(blocked)
+}
+
+// samePath returns whether a and b reference the same item.
+func samePath(a, b []string) bool {
+	return len(a) == len(b) && isPrefix(a, b)
+}
+
+// displayPath returns a path as it is displayed to users.
+func displayPath(path []string) string {
+	return strings.Join(path, " > ")
+}
+
+// blockers returns the paths of the items that still block item, i.e. those
+// that exist and aren't done.
+func (tl *List) blockers(item *Item) [][]string {
+	var open [][]string
+	for _, b := range item.BlockedBy {
+		if blocker, err := tl.get(b); err == nil && !blocker.Done {
+			open = append(open, b)
+		}
+	}
+	return open
+}
+
+// blockedSuffix returns the text displayed after a blocked item.
+func (tl *List) blockedSuffix(item *Item) string {
+	open := tl.blockers(item)
+	if len(open) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(open))
+	for _, b := range open {
+		names = append(names, displayPath(b))
+	}
+	return fmt.Sprintf(" (blocked by %s)", strings.Join(names, ", "))
+}
+
+// blockCycle returns the chain of items from blocker to blocked if blocker is
+// (directly or indirectly) blocked by blocked.
+func (tl *List) blockCycle(blocked, blocker []string) [][]string {
+	seen := map[string]bool{}
+	var visit func(path []string) [][]string
+	visit = func(path []string) [][]string {
+		if samePath(path, blocked) {
+			return [][]string{path}
+		}
+		key := strings.Join(path, "\x00")
+		if seen[key] {
+			return nil
+		}
+		seen[key] = true
+
+		item, err := tl.get(path)
+		if err != nil {
+			return nil
+		}
+		for _, b := range item.BlockedBy {
+			if chain := visit(b); chain != nil {
+				return append([][]string{path}, chain...)
+			}
+		}
+		return nil
+	}
+	return visit(blocker)
+}
+
+// BlockItem records that an item can't be done until another item is done.
+func (tl *List) BlockItem(output command.Output, data *command.Data) error {
+	path := data.StringList(pathArg)
+	item, err := tl.get(path)
+	if err != nil {
+		return output.Stderrf("%v\n", err)
+	}
+	if !data.Has(onFlag) {
+		return output.Stderrf("no blocking item provided (use --%s)\n", onFlag)
+	}
+	blocker := data.StringList(onFlag)
+	if _, err := tl.get(blocker); err != nil {
+		return output.Stderrf("%v\n", err)
+	}
+
+	if samePath(path, blocker) {
+		return output.Stderrf("item %s can't block itself\n", pathString(path))
+	}
+	for _, b := range item.BlockedBy {
+		if samePath(b, blocker) {
+			return output.Stderrf("item %s is already blocked by %s\n", pathString(path), pathString(blocker))
+		}
+	}
+	if cycle := tl.blockCycle(path, blocker); cycle != nil {
+		names := []string{displayPath(path)}
+		for _, p := range cycle {
+			names = append(names, displayPath(p))
+		}
+		return output.Stderrf("can't block %s on %s since it would create a cycle: %s\n", pathString(path), pathString(blocker), strings.Join(names, " -> "))
+	}
+
+	item.BlockedBy = append(item.BlockedBy, append([]string{}, blocker...))
+	item.touch()
+	tl.changed = true
+	return nil
+}
+
+// UnblockItem removes a blocker from an item. If no blocker is provided, all of
+// the item's blockers are removed.
+func (tl *List) UnblockItem(output command.Output, data *command.Data) error {
+	path := data.StringList(pathArg)
+	item, err := tl.get(path)
+	if err != nil {
+		return output.Stderrf("%v\n", err)
+	}
+	if len(item.BlockedBy) == 0 {
+		return output.Stderrf("item %s is not blocked\n", pathString(path))
+	}
+
+	if !data.Has(onFlag) {
+		item.BlockedBy = nil
+		item.touch()
+		tl.changed = true
+		return nil
+	}
+
+	blocker := data.StringList(onFlag)
+	var blockedBy [][]string
+	for _, b := range item.BlockedBy {
+		if !samePath(b, blocker) {
+			blockedBy = append(blockedBy, b)
+		}
+	}
+	if len(blockedBy) == len(item.BlockedBy) {
+		return output.Stderrf("item %s is not blocked by %s\n", pathString(path), pathString(blocker))
+	}
+	item.BlockedBy = blockedBy
+	item.touch()
+	tl.changed = true
+	return nil
+}
+
+// walkItems calls f for every item in the list, including sub-items.
+func (tl *List) walkItems(f func(path []string, item *Item)) {
+	var walk func(path []string, items map[string]*Item)
+	walk = func(path []string, items map[string]*Item) {
+		for _, k := range sortedKeys(items) {
+			p := append(append([]string{}, path...), k)
+			f(p, items[k])
+			walk(p, items[k].Items)
+		}
+	}
+	walk(nil, tl.Items)
+}
+
+// reportUnblocked outputs the items that were blocked by the item at path and
+// no longer have any open blockers.
+func (tl *List) reportUnblocked(output command.Output, path []string) {
+	tl.walkItems(func(p []string, item *Item) {
+		if item.Done || len(tl.blockers(item)) > 0 {
+			return
+		}
+		for _, b := range item.BlockedBy {
+			if samePath(b, path) {
+				output.Stdoutf("Unblocked %s\n", pathString(p))
+				return
+			}
+		}
+	})
+}
+
+// releaseBlockers removes the blockers that reference the item at path (or
+// one of its sub-items) from all other items and outputs the items that are
+// no longer blocked as a result. It is used when the item is deleted or
+// archived (so its path can't be reused by a new item) and when an occurrence
+// of a recurring item is completed (which satisfies the items waiting on it).
+func (tl *List) releaseBlockers(output command.Output, path []string) {
+	tl.walkItems(func(p []string, item *Item) {
+		if isPrefix(path, p) {
+			return
+		}
+		var kept [][]string
+		for _, b := range item.BlockedBy {
+			if !isPrefix(path, b) {
+				kept = append(kept, b)
+			}
+		}
+		if len(kept) == len(item.BlockedBy) {
+			return
+		}
+		wasBlocked := len(tl.blockers(item)) > 0
+		item.BlockedBy = kept
+		if wasBlocked && !item.Done && len(tl.blockers(item)) == 0 {
+			output.Stdoutf("Unblocked %s\n", pathString(p))
+		}
+	})
+}
+
+// moveBlockers updates the blockers that reference src (or its sub-items) to
+// reference dst instead.
+func (tl *List) moveBlockers(src, dst []string) {
+	tl.walkItems(func(_ []string, item *Item) {
+		for i, b := range item.BlockedBy {
+			if isPrefix(src, b) {
+				item.BlockedBy[i] = append(append([]string{}, dst...), b[len(src):]...)
+			}
+		}
+	})
+}
@@ -0,0 +1,525 @@
+package todo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/leep-frog/command"
+	"github.com/leep-frog/command/color"
+)
+
+func TestBlocks(t *testing.T) {
+	// rollout returns a list where deploy is blocked by build, which is blocked
+	// by review under another primary.
+	rollout := func() *List {
+		return &List{
+			Items: map[string]*Item{
+				"rollout": {
+					Items: map[string]*Item{
+						"build": {
+							BlockedBy: [][]string{{"code", "review"}},
+						},
+						"deploy": {
+							BlockedBy: [][]string{{"rollout", "build"}},
+						},
+					},
+				},
+				"code": {
+					Items: map[string]*Item{
+						"review": {},
+					},
+				},
+			},
+		}
+	}
+
+	for _, test := range []struct {
+		name       string
+		l          *List
+		args       []string
+		wantData   *command.Data
+		wantStdout []string
+		wantStderr string
+		want       *List
+	}{
+		{
+			name: "blocks an item on an item under another primary",
+			l: &List{
+				Items: map[string]*Item{
+					"rollout": {
+						Items: map[string]*Item{
+							"deploy": {},
+						},
+					},
+					"code": {
+						Items: map[string]*Item{
+							"review": {},
+						},
+					},
+				},
+			},
+			args: []string{"block", "rollout", "deploy", "--on", "code", "review"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"rollout", "deploy"},
+					onFlag:  []string{"code", "review"},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"rollout": {
+						Items: map[string]*Item{
+							"deploy": {
+								BlockedBy: [][]string{{"code", "review"}},
+								Updated:   testTime,
+							},
+						},
+					},
+					"code": {
+						Items: map[string]*Item{
+							"review": {},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "errors without a blocker",
+			l:    rollout(),
+			args: []string{"block", "rollout", "deploy"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"rollout", "deploy"},
+				},
+			},
+			wantStderr: "no blocking item provided (use --on)\n",
+		},
+		{
+			name: "errors on missing blocker",
+			l:    rollout(),
+			args: []string{"block", "rollout", "deploy", "-o", "code", "tests"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"rollout", "deploy"},
+					onFlag:  []string{"code", "tests"},
+				},
+			},
+			wantStderr: "item \"code\", \"tests\" does not exist\n",
+		},
+		{
+			name: "errors on missing item",
+			l:    rollout(),
+			args: []string{"block", "rollout", "verify", "-o", "rollout", "deploy"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"rollout", "verify"},
+					onFlag:  []string{"rollout", "deploy"},
+				},
+			},
+			wantStderr: "item \"rollout\", \"verify\" does not exist\n",
+		},
+		{
+			name: "errors when blocking an item on itself",
+			l:    rollout(),
+			args: []string{"block", "rollout", "deploy", "-o", "rollout", "deploy"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"rollout", "deploy"},
+					onFlag:  []string{"rollout", "deploy"},
+				},
+			},
+			wantStderr: "item \"rollout\", \"deploy\" can't block itself\n",
+		},
+		{
+			name: "errors when already blocked",
+			l:    rollout(),
+			args: []string{"block", "rollout", "deploy", "-o", "rollout", "build"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"rollout", "deploy"},
+					onFlag:  []string{"rollout", "build"},
+				},
+			},
+			wantStderr: "item \"rollout\", \"deploy\" is already blocked by \"rollout\", \"build\"\n",
+		},
+		{
+			name: "rejects cycles",
+			l:    rollout(),
+			args: []string{"block", "code", "review", "-o", "rollout", "deploy"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"code", "review"},
+					onFlag:  []string{"rollout", "deploy"},
+				},
+			},
+			wantStderr: "can't block \"code\", \"review\" on \"rollout\", \"deploy\" since it would create a cycle: code > review -> rollout > deploy -> rollout > build -> code > review\n",
+		},
+		{
+			name: "unblocks an item from a blocker",
+			l: &List{
+				Items: map[string]*Item{
+					"rollout": {
+						Items: map[string]*Item{
+							"build": {},
+							"deploy": {
+								BlockedBy: [][]string{{"rollout", "build"}, {"code", "review"}},
+							},
+						},
+					},
+				},
+			},
+			args: []string{"unblock", "rollout", "deploy", "--on", "rollout", "build"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"rollout", "deploy"},
+					onFlag:  []string{"rollout", "build"},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"rollout": {
+						Items: map[string]*Item{
+							"build": {},
+							"deploy": {
+								BlockedBy: [][]string{{"code", "review"}},
+								Updated:   testTime,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "unblocks an item from all blockers",
+			l: &List{
+				Items: map[string]*Item{
+					"rollout": {
+						Items: map[string]*Item{
+							"deploy": {
+								BlockedBy: [][]string{{"rollout", "build"}, {"code", "review"}},
+							},
+						},
+					},
+				},
+			},
+			args: []string{"unblock", "rollout", "deploy"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"rollout", "deploy"},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"rollout": {
+						Items: map[string]*Item{
+							"deploy": {
+								Updated: testTime,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "errors when unblocking an item that isn't blocked",
+			l:    rollout(),
+			args: []string{"unblock", "code", "review"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"code", "review"},
+				},
+			},
+			wantStderr: "item \"code\", \"review\" is not blocked\n",
+		},
+		{
+			name: "errors when unblocking an item from an item that doesn't block it",
+			l:    rollout(),
+			args: []string{"unblock", "rollout", "deploy", "-o", "code", "review"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"rollout", "deploy"},
+					onFlag:  []string{"code", "review"},
+				},
+			},
+			wantStderr: "item \"rollout\", \"deploy\" is not blocked by \"code\", \"review\"\n",
+		},
+		{
+			name: "dims blocked items",
+			l:    rollout(),
+			wantStdout: []string{
+				"code",
+				"  [ ] review",
+				"rollout",
+				"  \033[2m[ ] build\033[22m\033[2m (blocked by code > review)\033[22m",
+				"  \033[2m[ ] deploy\033[22m\033[2m (blocked by rollout > build)\033[22m",
+			},
+		},
+		{
+			name: "dims blocked items without hiding that they are overdue or due today",
+			l: &List{
+				Items: map[string]*Item{
+					"code": {
+						Items: map[string]*Item{
+							"review": {Due: testTime.AddDate(0, 0, -2)},
+						},
+					},
+					"rollout": {
+						Items: map[string]*Item{
+							"build": {
+								Priority:  "P1",
+								Due:       testTime.AddDate(0, 0, -1),
+								Tags:      []string{"@ops"},
+								BlockedBy: [][]string{{"code", "review"}},
+							},
+							"deploy": {
+								Due:       testTime.Truncate(24 * time.Hour),
+								BlockedBy: [][]string{{"rollout", "build"}},
+							},
+						},
+					},
+				},
+			},
+			wantStdout: []string{
+				"code",
+				"  [ ] review " + color.Red.Format("(due 2023-02-01)"),
+				"rollout",
+				"  " + faint("[ ] build") + faint(" [P1]") + faint(" "+color.Red.Format("(due 2023-02-02)")) + faint(" {@ops}") + faint(" (blocked by code > review)"),
+				"  " + faint("[ ] deploy") + faint(" "+color.Yellow.Format("(due 2023-02-03)")) + faint(" (blocked by rollout > build)"),
+			},
+		},
+		{
+			name: "completing a blocker reports the items it unblocked",
+			l:    rollout(),
+			args: []string{"c", "code", "review"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"code", "review"},
+				},
+			},
+			wantStdout: []string{
+				"Unblocked \"rollout\", \"build\"",
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"rollout": {
+						Items: map[string]*Item{
+							"build": {
+								BlockedBy: [][]string{{"code", "review"}},
+							},
+							"deploy": {
+								BlockedBy: [][]string{{"rollout", "build"}},
+							},
+						},
+					},
+					"code": {
+						Items: map[string]*Item{
+							"review": {
+								Done:    true,
+								Updated: testTime,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "doesn't report items that are still blocked",
+			l: &List{
+				Items: map[string]*Item{
+					"rollout": {
+						Items: map[string]*Item{
+							"build": {},
+							"test":  {},
+							"deploy": {
+								BlockedBy: [][]string{{"rollout", "build"}, {"rollout", "test"}},
+							},
+						},
+					},
+				},
+			},
+			args: []string{"c", "rollout", "build"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"rollout", "build"},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"rollout": {
+						Items: map[string]*Item{
+							"build": {
+								Done:    true,
+								Updated: testTime,
+							},
+							"test": {},
+							"deploy": {
+								BlockedBy: [][]string{{"rollout", "build"}, {"rollout", "test"}},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "deleting a blocker removes it from the items it blocks",
+			l:    rollout(),
+			args: []string{"d", "code", "review"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"code", "review"},
+				},
+			},
+			wantStdout: []string{
+				"Unblocked \"rollout\", \"build\"",
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"rollout": {
+						Items: map[string]*Item{
+							"build": {},
+							"deploy": {
+								BlockedBy: [][]string{{"rollout", "build"}},
+							},
+						},
+					},
+					"code": {
+						Items: map[string]*Item{},
+					},
+				},
+			},
+		},
+		{
+			name: "archiving a blocker's parent removes it from the items it blocks",
+			l:    rollout(),
+			args: []string{"archive", "code"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"code"},
+				},
+			},
+			wantStdout: []string{
+				"Unblocked \"rollout\", \"build\"",
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"rollout": {
+						Items: map[string]*Item{
+							"build": {},
+							"deploy": {
+								BlockedBy: [][]string{{"rollout", "build"}},
+							},
+						},
+					},
+				},
+				Archive: []*ArchivedItem{
+					{
+						Path: []string{"code"},
+						Item: &Item{
+							Items: map[string]*Item{
+								"review": {},
+							},
+						},
+						Archived: testTime,
+					},
+				},
+			},
+		},
+		{
+			name: "completing a recurring blocker releases the items it blocks",
+			l: &List{
+				Items: map[string]*Item{
+					"rollout": {
+						Items: map[string]*Item{
+							"build": {
+								BlockedBy: [][]string{{"code", "review"}},
+							},
+						},
+					},
+					"code": {
+						Items: map[string]*Item{
+							"review": {
+								Repeat: "daily",
+								Due:    testTime.Truncate(24 * time.Hour),
+							},
+						},
+					},
+				},
+			},
+			args: []string{"c", "code", "review"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"code", "review"},
+				},
+			},
+			wantStdout: []string{
+				"Item \"code\", \"review\" repeats daily; next due 2023-02-04",
+				"Unblocked \"rollout\", \"build\"",
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"rollout": {
+						Items: map[string]*Item{
+							"build": {},
+						},
+					},
+					"code": {
+						Items: map[string]*Item{
+							"review": {
+								Repeat:  "daily",
+								Due:     time.Date(2023, time.February, 4, 0, 0, 0, 0, time.UTC),
+								Updated: testTime,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "moving a blocker updates the items it blocks",
+			l:    rollout(),
+			args: []string{"mv", "code", "review", "rollout", "review"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"code", "review", "rollout", "review"},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"rollout": {
+						Order: []string{"build", "deploy", "review"},
+						Items: map[string]*Item{
+							"build": {
+								BlockedBy: [][]string{{"rollout", "review"}},
+							},
+							"deploy": {
+								BlockedBy: [][]string{{"rollout", "build"}},
+							},
+							"review": {
+								Updated: testTime,
+							},
+						},
+					},
+					"code": {
+						Items: map[string]*Item{},
+					},
+				},
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			stubNow(t, testTime)
+			executeTest(t, test.l, test.args, test.wantData, test.wantStdout, test.wantStderr)
+			command.ChangeTest(t, test.want, test.l, cmp.AllowUnexported(List{}), ignoreHistory, ignoreLog)
+		})
+	}
+}
@@ -7,6 +7,10 @@ import (
 	"github.com/leep-frog/todo"
 )
 
+// main sources the td CLI. Separate lists (e.g. for work and home items) can
+// be sourced alongside it with todo.NamedCLI:
+//
+//	sourcerer.Source(todo.CLI(), todo.NamedCLI("tdw"), todo.NamedCLI("tdh"))
 func main() {
 	os.Exit(sourcerer.Source(todo.CLI()))
 }
@@ -1,39 +1,80 @@
 package todo
 
 import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+	"time"
+
 	"github.com/leep-frog/command"
 	"github.com/leep-frog/command/color"
 )
 
 const (
-	primaryArg    = "primary"
-	primaryDesc   = "Primary list"
-	secondaryArg  = "secondary"
-	secondaryDesc = "Secondary list"
+	primaryArg   = "primary"
+	primaryDesc  = "Primary list"
+	pathArg      = "path"
+	pathDesc     = "Path of items, starting with the primary item"
+	hideDoneFlag = "hide-done"
+	verboseFlag  = "verbose"
+
+	recursiveFlag   = "recursive"
+	forceFlag       = "force"
+	confirmOverFlag = "confirm-over"
+
+	// defaultConfirmOver is the number of items a recursive delete can remove
+	// before confirmation is required.
+	defaultConfirmOver = 5
+)
+
+var (
+	// stdin is where confirmation responses are read from.
+	stdin io.Reader = os.Stdin
 )
 
 func (tl *List) AddItem(output command.Output, data *command.Data) error {
 	if tl.Items == nil {
-		tl.Items = map[string]map[string]bool{}
-		tl.changed = true
+		tl.Items = map[string]*Item{}
 	}
 
-	p := data.String(primaryArg)
-	if _, ok := tl.Items[p]; !ok {
-		tl.Items[p] = map[string]bool{}
-		tl.changed = true
+	t := now()
+	var due time.Time
+	if data.Has(dueFlag) {
+		var err error
+		if due, err = parseDue(data.String(dueFlag), t); err != nil {
+			return output.Stderrf("%v\n", err)
+		}
 	}
 
-	if data.Has(secondaryArg) {
-		s := data.String(secondaryArg)
-		if tl.Items[p][s] {
-			return output.Stderrf("item %q, %q already exists\n", p, s)
+	path := data.StringList(pathArg)
+	items := tl.Items
+	added := false
+	for i, p := range path {
+		item, ok := items[p]
+		if !ok {
+			item = &Item{
+				Created: t,
+				Updated: t,
+			}
+			items[p] = item
+			tl.appendOrder(path[:i], p)
+			tl.logEvent(eventAdded, path[:i+1])
+			added = true
+		}
+		if i < len(path)-1 && item.Items == nil {
+			item.Items = map[string]*Item{}
 		}
-		tl.Items[p][s] = true
-		tl.changed = true
-	} else if !tl.changed {
-		return output.Stderrf("primary item %q already exists\n", p)
+		if i == len(path)-1 && !ok {
+			item.Due = due
+		}
+		items = item.Items
+	}
+
+	if !added {
+		return output.Stderrf("item %s already exists\n", pathString(path))
 	}
+	tl.changed = true
 	return nil
 }
 
@@ -42,82 +83,495 @@ func (tl *List) DeleteItem(output command.Output, data *command.Data) error {
 		return output.Stderr("can't delete from empty list\n")
 	}
 
-	p := data.String(primaryArg)
-	if _, ok := tl.Items[p]; !ok {
-		return output.Stderrf("Primary item %q does not exist\n", p)
+	path := data.StringList(pathArg)
+	item, err := tl.get(path)
+	if err != nil {
+		return output.Stderrf("%v\n", err)
+	}
+
+	if len(item.Items) != 0 {
+		if !data.Bool(recursiveFlag) {
+			return output.Stderr("Can't delete item that still has sub-items\n")
+		}
+
+		output.Stdoutln(pathString(path))
+		listItems(output, item, 1, &listOptions{})
+
+		confirmOver := defaultConfirmOver
+		if data.Has(confirmOverFlag) {
+			confirmOver = data.Int(confirmOverFlag)
+		}
+		if n := item.count(); n > confirmOver && !data.Bool(forceFlag) {
+			output.Stdoutf("Delete %d items? [y/N] ", n)
+			if !confirmed() {
+				return output.Stderr("delete aborted\n")
+			}
+		}
+	}
+
+	tl.releaseBlockers(output, path)
+	delete(tl.children(path[:len(path)-1]), path[len(path)-1])
+	tl.removeOrder(path[:len(path)-1], path[len(path)-1])
+	if len(path) == 1 {
+		delete(tl.PrimaryFormats, path[0])
+	}
+	tl.logEvent(eventDeleted, path)
+	tl.changed = true
+	return nil
+}
+
+// confirmed reads a response from stdin and returns whether it was affirmative.
+func confirmed() bool {
+	response, err := bufio.NewReader(stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(response)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// MoveItem renames an item or moves it under another item. If the provided
+// path contains a valueSeparator, the source is everything before it and the
+// destination is everything after it. Otherwise, the source is the longest
+// prefix of the provided path that references an existing item and the
+// remaining elements are the destination, so the separator is needed when the
+// destination starts with the name of one of the source's sub-items. If the
+// destination already exists, the source is moved under it.
+func (tl *List) MoveItem(output command.Output, data *command.Data) error {
+	src, dst, ok := cutSeparator(data.StringList(pathArg))
+	if ok {
+		if len(src) == 0 {
+			return output.Stderrf("no item provided before %q\n", valueSeparator)
+		}
+		if _, err := tl.get(src); err != nil {
+			return output.Stderrf("%v\n", err)
+		}
+	} else {
+		src, dst = tl.splitPath(src)
+		if len(src) == 0 {
+			return output.Stderrf("item %s does not exist\n", pathString(dst[:1]))
+		}
+	}
+	if len(dst) == 0 {
+		return output.Stderrf("no destination provided (separate the item and its destination with %q)\n", valueSeparator)
+	}
+
+	if _, err := tl.get(dst); err == nil {
+		dst = append(append([]string{}, dst...), src[len(src)-1])
+	}
+	if isPrefix(src, dst) {
+		return output.Stderr("can't move an item into itself\n")
+	}
+
+	dstParent, dstName := dst[:len(dst)-1], dst[len(dst)-1]
+	if _, err := tl.get(dstParent); err != nil {
+		return output.Stderrf("%v\n", err)
+	}
+	if _, err := tl.get(dst); err == nil {
+		return output.Stderrf("item %s already exists\n", pathString(dst))
+	}
+
+	srcItems := tl.children(src[:len(src)-1])
+	item := srcItems[src[len(src)-1]]
+	delete(srcItems, src[len(src)-1])
+	tl.removeOrder(src[:len(src)-1], src[len(src)-1])
+	item.touch()
+
+	if len(dstParent) == 0 {
+		tl.Items[dstName] = item
+	} else {
+		parent, _ := tl.get(dstParent)
+		if parent.Items == nil {
+			parent.Items = map[string]*Item{}
+		}
+		parent.Items[dstName] = item
+	}
+	tl.appendOrder(dstParent, dstName)
+	tl.moveBlockers(src, dst)
+	tl.moveTimer(src, dst)
+	tl.movePomodoro(src, dst)
+
+	// Formats only apply to primary items.
+	if len(src) == 1 {
+		f, ok := tl.PrimaryFormats[src[0]]
+		delete(tl.PrimaryFormats, src[0])
+		if ok && len(dst) == 1 {
+			tl.PrimaryFormats[dstName] = f
+		}
+	}
+	tl.changed = true
+	return nil
+}
+
+// CompleteItem marks an item as done.
+func (tl *List) CompleteItem(output command.Output, data *command.Data) error {
+	return tl.setDone(output, data, true)
+}
+
+// UncompleteItem marks an item as not done.
+func (tl *List) UncompleteItem(output command.Output, data *command.Data) error {
+	return tl.setDone(output, data, false)
+}
+
+func (tl *List) setDone(output command.Output, data *command.Data, done bool) error {
+	path := data.StringList(pathArg)
+	item, err := tl.get(path)
+	if err != nil {
+		return output.Stderrf("%v\n", err)
 	}
 
-	// Delete secondary if provided
-	if data.Has(secondaryArg) {
-		s := data.String(secondaryArg)
-		if tl.Items[p][s] {
-			delete(tl.Items[p], s)
-			tl.changed = true
-			return nil
-		} else {
-			return output.Stderrf("Secondary item %q does not exist\n", s)
+	if item.Done == done {
+		if done {
+			return output.Stderrf("item %s is already done\n", pathString(path))
 		}
+		return output.Stderrf("item %s is not done\n", pathString(path))
+	}
+	if done && item.Repeat != "" {
+		return tl.completeRecurring(output, path, item)
 	}
+	item.Done = done
+	item.touch()
+	if done {
+		tl.logEvent(eventCompleted, path)
+		tl.reportUnblocked(output, path)
+	} else {
+		tl.logEvent(eventReopened, path)
+	}
+	tl.changed = true
+	return nil
+}
 
-	if len(tl.Items[p]) != 0 {
-		return output.Stderr("Can't delete primary item that still has secondary items\n")
+// SetNote sets the note of an item to the text provided after its path (see
+// splitValues). If no text is provided, the note is removed.
+func (tl *List) SetNote(output command.Output, data *command.Data) error {
+	_, item, text, err := tl.splitValues(data.StringList(pathArg))
+	if err != nil {
+		return output.Stderrf("%v\n", err)
 	}
 
-	delete(tl.Items, p)
+	note := strings.Join(text, " ")
+	if item.Note == note {
+		return nil
+	}
+	item.Note = note
+	item.touch()
 	tl.changed = true
 	return nil
 }
 
 // Name returns the name of the CLI.
 func (tl *List) Name() string {
-	return "td"
+	if tl.name == "" {
+		return defaultName
+	}
+	return tl.name
 }
 
-func completer(l *List, primary bool) command.Completer[string] {
+func primaryCompleter(l *List) command.Completer[string] {
 	return command.CompleterFromFunc(func(value string, data *command.Data) (*command.Completion, error) {
-		if primary {
-			primaries := make([]string, 0, len(l.Items))
-			for p := range l.Items {
-				primaries = append(primaries, p)
+		return &command.Completion{
+			Suggestions: sortedKeys(l.Items),
+		}, nil
+	})
+}
+
+// pathCompleter suggests the sub-items of the item referenced by all but the
+// last element of the path.
+func pathCompleter(l *List) command.Completer[[]string] {
+	return command.CompleterFromFunc(func(path []string, data *command.Data) (*command.Completion, error) {
+		if len(path) == 0 {
+			return &command.Completion{
+				Suggestions: sortedKeys(l.Items),
+			}, nil
+		}
+		return &command.Completion{
+			Suggestions: sortedKeys(l.children(path[:len(path)-1])),
+		}, nil
+	})
+}
+
+// moveCompleter suggests sub-items while the path still references an existing
+// item (along with primary items to start the destination), and then suggests
+// sub-items of the destination path. After a valueSeparator, only the
+// destination path is completed.
+func moveCompleter(l *List) command.Completer[[]string] {
+	return command.CompleterFromFunc(func(path []string, data *command.Data) (*command.Completion, error) {
+		if len(path) == 0 {
+			return &command.Completion{
+				Suggestions: sortedKeys(l.Items),
+			}, nil
+		}
+
+		prev, dst, ok := cutSeparator(path[:len(path)-1])
+		if ok {
+			return &command.Completion{
+				Suggestions: sortedKeys(l.children(dst)),
+			}, nil
+		}
+
+		src, dst := l.splitPath(prev)
+		if len(dst) != 0 {
+			return &command.Completion{
+				Suggestions: sortedKeys(l.children(dst)),
+			}, nil
+		}
+
+		suggestions := sortedKeys(l.children(src))
+		if len(src) != 0 {
+			suggestions = append(suggestions, sortedKeys(l.Items)...)
+		}
+		return &command.Completion{
+			Suggestions: suggestions,
+		}, nil
+	})
+}
+
+// suggest returns a completer that suggests a fixed set of values.
+func suggest(values []string) command.Completer[string] {
+	return command.CompleterFromFunc(func(string, *command.Data) (*command.Completion, error) {
+		return &command.Completion{
+			Suggestions: values,
+		}, nil
+	})
+}
+
+// valueCompleter suggests sub-items while the path references an existing
+// item. Once the path references an item, the values returned by f (if any)
+// are also suggested. After a valueSeparator, only the values are suggested.
+func valueCompleter(l *List, f func() []string) command.Completer[[]string] {
+	return command.CompleterFromFunc(func(path []string, data *command.Data) (*command.Completion, error) {
+		if len(path) == 0 {
+			return &command.Completion{
+				Suggestions: sortedKeys(l.Items),
+			}, nil
+		}
+
+		var values []string
+		if f != nil {
+			values = f()
+		}
+		prev, _, ok := cutSeparator(path[:len(path)-1])
+		if ok {
+			if len(prev) == 0 || len(values) == 0 {
+				return nil, nil
 			}
 			return &command.Completion{
-				Suggestions: primaries,
+				Suggestions: values,
 			}, nil
 		}
 
-		p := data.String(primaryArg)
-		sMap := l.Items[p]
-		secondaries := make([]string, 0, len(sMap))
-		for s := range sMap {
-			secondaries = append(secondaries, s)
+		existing, rest := l.splitPath(prev)
+		if len(rest) != 0 {
+			return nil, nil
+		}
+		suggestions := sortedKeys(l.children(existing))
+		if len(existing) != 0 {
+			suggestions = append(suggestions, values...)
 		}
 		return &command.Completion{
-			Suggestions: secondaries,
+			Suggestions: suggestions,
 		}, nil
 	})
 }
 
 func (tl *List) Node() command.Node {
-	pf := completer(tl, true)
-	sf := completer(tl, false)
+	pc := pathCompleter(tl)
 	return &command.BranchNode{
 		Branches: map[string]command.Node{
 			"a": command.SerialNodes(
-				command.Arg[string](primaryArg, primaryDesc, pf),
-				command.OptionalArg[string](secondaryArg, secondaryDesc),
-				&command.ExecutorProcessor{F: tl.AddItem},
+				command.FlagNode(
+					command.NewFlag[string](dueFlag, 'd', "When the item is due", suggest(dueSuggestions)),
+				),
+				command.ListArg[string](pathArg, pathDesc, 1, command.UnboundedList, pc),
+				&command.ExecutorProcessor{F: tl.recorded(tl.AddItem)},
 			),
 			"d": command.SerialNodes(
-				command.Arg[string](primaryArg, primaryDesc, pf),
-				command.OptionalArg[string](secondaryArg, secondaryDesc, sf),
-				&command.ExecutorProcessor{F: tl.DeleteItem},
+				command.FlagNode(
+					command.BoolFlag(recursiveFlag, 'r', "Delete the item and all of its sub-items"),
+					command.BoolFlag(forceFlag, 'f', "Don't prompt for confirmation"),
+					command.NewFlag[int](confirmOverFlag, 'c', "Prompt for confirmation when a recursive delete removes more than this many items"),
+				),
+				command.ListArg[string](pathArg, pathDesc, 1, command.UnboundedList, pc),
+				&command.ExecutorProcessor{F: tl.recorded(tl.DeleteItem)},
+			),
+			"mv": command.SerialNodes(
+				command.ListArg[string](pathArg, "Path of the item followed by its destination (optionally after --)", 2, command.UnboundedList, moveCompleter(tl)),
+				&command.ExecutorProcessor{F: tl.recorded(tl.MoveItem)},
+			),
+			"c": command.SerialNodes(
+				command.ListArg[string](pathArg, pathDesc, 2, command.UnboundedList, pc),
+				&command.ExecutorProcessor{F: tl.recorded(tl.CompleteItem)},
+			),
+			"u": command.SerialNodes(
+				command.ListArg[string](pathArg, pathDesc, 2, command.UnboundedList, pc),
+				&command.ExecutorProcessor{F: tl.recorded(tl.UncompleteItem)},
+			),
+			"block": command.SerialNodes(
+				command.FlagNode(
+					command.ListFlag[string](onFlag, 'o', "Path of the item that needs to be done first", 2, command.UnboundedList, pc),
+				),
+				command.ListArg[string](pathArg, pathDesc, 2, command.UnboundedList, pc),
+				&command.ExecutorProcessor{F: tl.recorded(tl.BlockItem)},
+			),
+			"unblock": command.SerialNodes(
+				command.FlagNode(
+					command.ListFlag[string](onFlag, 'o', "Path of the blocking item to remove (all are removed if not provided)", 2, command.UnboundedList, pc),
+				),
+				command.ListArg[string](pathArg, pathDesc, 2, command.UnboundedList, pc),
+				&command.ExecutorProcessor{F: tl.recorded(tl.UnblockItem)},
 			),
 			"f": command.SerialNodes(
-				command.Arg[string](primaryArg, primaryDesc, pf),
+				command.Arg[string](primaryArg, primaryDesc, primaryCompleter(tl)),
 				color.Arg,
-				&command.ExecutorProcessor{F: tl.FormatPrimary},
+				&command.ExecutorProcessor{F: tl.recorded(tl.FormatPrimary)},
+			),
+			"note": command.SerialNodes(
+				command.ListArg[string](pathArg, "Path of the item followed by the note's text (optionally after --)", 1, command.UnboundedList, valueCompleter(tl, nil)),
+				&command.ExecutorProcessor{F: tl.recorded(tl.SetNote)},
+			),
+			"due": command.SerialNodes(
+				command.ListArg[string](pathArg, "Path of the item followed by when it is due (or none)", 1, command.UnboundedList, valueCompleter(tl, func() []string { return dueSuggestions })),
+				&command.ExecutorProcessor{F: tl.recorded(tl.SetDue)},
+			),
+			"repeat": command.SerialNodes(
+				command.ListArg[string](pathArg, "Path of the item followed by its recurrence rule (or none)", 1, command.UnboundedList, valueCompleter(tl, func() []string { return repeatSuggestions })),
+				&command.ExecutorProcessor{F: tl.recorded(tl.SetRepeat)},
+			),
+			"p": command.SerialNodes(
+				command.ListArg[string](pathArg, "Path of the item followed by its priority (or none)", 1, command.UnboundedList, valueCompleter(tl, func() []string { return priorities })),
+				&command.ExecutorProcessor{F: tl.recorded(tl.SetPriority)},
+			),
+			"order": command.SerialNodes(
+				command.ListArg[string](pathArg, orderDesc, 2, command.UnboundedList, valueCompleter(tl, func() []string { return orderSuggestions })),
+				&command.ExecutorProcessor{F: tl.recorded(tl.OrderItem)},
+			),
+			"s": command.SerialNodes(
+				command.FlagNode(
+					command.BoolFlag(ignoreCaseFlag, 'i', "Ignore case when matching"),
+					command.BoolFlag(regexFlag, 'r', "Treat the pattern as a regular expression"),
+				),
+				command.Arg[string](patternArg, "Text to search for in item names and notes"),
+				&command.ExecutorProcessor{F: tl.Search},
+			),
+			"tag": command.SerialNodes(
+				command.ListArg[string](pathArg, "Path of the item followed by the tags to add (optionally after --)", 2, command.UnboundedList, valueCompleter(tl, tl.tags)),
+				&command.ExecutorProcessor{F: tl.recorded(tl.TagItem)},
+			),
+			"untag": command.SerialNodes(
+				command.ListArg[string](pathArg, "Path of the item followed by the tags to remove (optionally after --)", 2, command.UnboundedList, valueCompleter(tl, tl.tags)),
+				&command.ExecutorProcessor{F: tl.recorded(tl.UntagItem)},
+			),
+			"export": &command.BranchNode{
+				Branches: map[string]command.Node{
+					"ics": command.SerialNodes(
+						&command.ExecutorProcessor{F: tl.ExportICS},
+					),
+					"md": command.SerialNodes(
+						&command.ExecutorProcessor{F: tl.ExportMarkdown},
+					),
+					"todotxt": command.SerialNodes(
+						&command.ExecutorProcessor{F: tl.ExportTodoTxt},
+					),
+				},
+			},
+			"import": &command.BranchNode{
+				Branches: map[string]command.Node{
+					"ics": command.SerialNodes(
+						command.FileArgument(fileArg, fileDesc),
+						&command.ExecutorProcessor{F: tl.recorded(tl.ImportICS)},
+					),
+					"md": command.SerialNodes(
+						command.FileArgument(fileArg, fileDesc),
+						&command.ExecutorProcessor{F: tl.recorded(tl.ImportMarkdown)},
+					),
+					"todotxt": command.SerialNodes(
+						command.FileArgument(fileArg, fileDesc),
+						&command.ExecutorProcessor{F: tl.recorded(tl.ImportTodoTxt)},
+					),
+				},
+			},
+			"archive": &command.BranchNode{
+				Branches: map[string]command.Node{
+					"ls": command.SerialNodes(
+						command.FlagNode(
+							command.NewFlag[string](sinceFlag, 's', "Only show items archived on or after this date", suggest(pastSuggestions)),
+							command.NewFlag[string](untilFlag, 'u', "Only show items archived on or before this date", suggest(pastSuggestions)),
+						),
+						&command.ExecutorProcessor{F: tl.ListArchive},
+					),
+					"restore": command.SerialNodes(
+						command.ListArg[string](pathArg, "Path of the archived item", 1, command.UnboundedList, archiveCompleter(tl)),
+						&command.ExecutorProcessor{F: tl.recorded(tl.RestoreItem)},
+					),
+				},
+				Default: command.SerialNodes(
+					command.ListArg[string](pathArg, pathDesc, 1, command.UnboundedList, pc),
+					&command.ExecutorProcessor{F: tl.recorded(tl.ArchiveItem)},
+				),
+			},
+			"start": command.SerialNodes(
+				command.ListArg[string](pathArg, pathDesc, 2, command.UnboundedList, pc),
+				&command.ExecutorProcessor{F: tl.recorded(tl.StartTimer)},
+			),
+			"stop": command.SerialNodes(
+				&command.ExecutorProcessor{F: tl.recorded(tl.StopTimer)},
+			),
+			"pomo": &command.BranchNode{
+				Branches: map[string]command.Node{
+					"wait": command.SerialNodes(
+						&command.ExecutorProcessor{F: tl.WaitPomodoro},
+					),
+					"cancel": command.SerialNodes(
+						&command.ExecutorProcessor{F: tl.recorded(tl.CancelPomodoro)},
+					),
+				},
+				Default: command.SerialNodes(
+					command.FlagNode(
+						command.NewFlag[string](workFlag, 'w', "Length of the work phase (default 25m)", suggest(pomoSuggestions)),
+						command.NewFlag[string](breakFlag, 'b', "Length of the break (default 5m)", suggest(breakSuggestions)),
+					),
+					command.ListArg[string](pathArg, pathDesc, 2, command.UnboundedList, pc),
+					&command.ExecutorProcessor{F: tl.recorded(tl.StartPomodoro)},
+				),
+			},
+			"time": command.SerialNodes(
+				command.FlagNode(
+					command.NewFlag[string](sinceFlag, 's', "Only include time spent on or after this date", suggest(pastSuggestions)),
+				),
+				&command.ExecutorProcessor{F: tl.ListTime},
+			),
+			"next": command.SerialNodes(
+				command.OptionalArg[int](countArg, "Number of items"),
+				&command.ExecutorProcessor{F: tl.Next},
+			),
+			"report": command.SerialNodes(
+				command.FlagNode(
+					command.NewFlag[string](sinceFlag, 's', "Only include changes made on or after this date (default 7d)", suggest(pastSuggestions)),
+					command.NewFlag[string](outputFormatFlag, 'f', "Output format", suggest(reportFormats)),
+				),
+				&command.ExecutorProcessor{F: tl.Report},
+			),
+			"undo": command.SerialNodes(
+				command.OptionalArg[int](countArg, countDesc),
+				&command.ExecutorProcessor{F: tl.Undo},
+			),
+			"redo": command.SerialNodes(
+				command.OptionalArg[int](countArg, countDesc),
+				&command.ExecutorProcessor{F: tl.Redo},
 			),
 		},
-		Default: command.SerialNodes(&command.ExecutorProcessor{F: tl.ListItems}),
+		Default: command.SerialNodes(
+			command.FlagNode(
+				command.BoolFlag(hideDoneFlag, 'h', "Hide completed items"),
+				command.BoolFlag(verboseFlag, 'v', "Show item metadata"),
+				command.NewFlag[string](sortFlag, 's', "How to order items", suggest(sortOptions)),
+				command.NewFlag[string](tagFlag, 't', "Only show items with this tag", tagCompleter(tl)),
+				command.NewFlag[string](outputFormatFlag, 'f', "Output format", suggest(outputFormats)),
+			),
+			&command.ExecutorProcessor{F: tl.ListItems},
+		),
 	}
 }
@@ -0,0 +1,193 @@
+package todo
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/leep-frog/command"
+	"github.com/leep-frog/command/color"
+)
+
+const (
+	dueFlag = "due"
+
+	dateFormat = "2006-01-02"
+)
+
+var (
+	overdueFormat  = &color.Format{Color: color.Red}
+	dueTodayFormat = &color.Format{Color: color.Yellow}
+
+	relativeDueRegex = regexp.MustCompile(`^\+([0-9]+)([dw])$`)
+	agoRegex         = regexp.MustCompile(`^([0-9]+)([dw])$`)
+
+	weekdays = map[string]time.Weekday{
+		"sun":       time.Sunday,
+		"sunday":    time.Sunday,
+		"mon":       time.Monday,
+		"monday":    time.Monday,
+		"tue":       time.Tuesday,
+		"tues":      time.Tuesday,
+		"tuesday":   time.Tuesday,
+		"wed":       time.Wednesday,
+		"wednesday": time.Wednesday,
+		"thu":       time.Thursday,
+		"thur":      time.Thursday,
+		"thurs":     time.Thursday,
+		"thursday":  time.Thursday,
+		"fri":       time.Friday,
+		"friday":    time.Friday,
+		"sat":       time.Saturday,
+		"saturday":  time.Saturday,
+	}
+
+	// dueSuggestions are the values suggested when completing a due date.
+	dueSuggestions = []string{"today", "tomorrow", "mon", "tue", "wed", "thu", "fri", "sat", "sun", "+1d", "+1w"}
+)
+
+// day returns midnight of the day containing t.
+func day(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// daysBetween returns the number of calendar days from the day of a to the day
+// of b. The days are compared as UTC dates so that daylight saving time
+// changes (which make some days shorter or longer than 24 hours) don't affect
+// the count.
+func daysBetween(a, b time.Time) int {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	d := time.Date(by, bm, bd, 0, 0, 0, 0, time.UTC).Sub(time.Date(ay, am, ad, 0, 0, 0, 0, time.UTC))
+	return int(d / (24 * time.Hour))
+}
+
+// parseDue parses a due date relative to the provided time. Supported values
+// are absolute dates (2006-01-02 or 01/02), "today", "tomorrow", weekday names
+// (which refer to the next occurrence of that day, including today), and
+// relative offsets such as "+3d" and "+2w".
+func parseDue(s string, from time.Time) (time.Time, error) {
+	today := day(from)
+	v := strings.ToLower(strings.TrimSpace(s))
+	switch v {
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	}
+
+	if wd, ok := weekdays[v]; ok {
+		return today.AddDate(0, 0, (int(wd)-int(today.Weekday())+7)%7), nil
+	}
+
+	if m := relativeDueRegex.FindStringSubmatch(v); m != nil {
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid due date %q: %v", s, err)
+		}
+		if m[2] == "w" {
+			n *= 7
+		}
+		return today.AddDate(0, 0, n), nil
+	}
+
+	if t, err := time.ParseInLocation(dateFormat, v, from.Location()); err == nil {
+		return t, nil
+	}
+
+	// Dates without a year refer to their next occurrence.
+	if t, err := time.ParseInLocation("01/02", v, from.Location()); err == nil {
+		t = time.Date(today.Year(), t.Month(), t.Day(), 0, 0, 0, 0, from.Location())
+		if t.Before(today) {
+			t = t.AddDate(1, 0, 0)
+		}
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid due date %q", s)
+}
+
+// parsePast parses a date relative to the provided time for use as the start
+// of a date range. Supported values are absolute dates (2006-01-02), "today",
+// "yesterday", weekday names (which refer to the most recent occurrence of
+// that day, including today), and offsets into the past such as "3d" and "2w".
+func parsePast(s string, from time.Time) (time.Time, error) {
+	today := day(from)
+	v := strings.ToLower(strings.TrimSpace(s))
+	switch v {
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+
+	if wd, ok := weekdays[v]; ok {
+		return today.AddDate(0, 0, -((int(today.Weekday()) - int(wd) + 7) % 7)), nil
+	}
+
+	if m := agoRegex.FindStringSubmatch(v); m != nil {
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date %q: %v", s, err)
+		}
+		if m[2] == "w" {
+			n *= 7
+		}
+		return today.AddDate(0, 0, -n), nil
+	}
+
+	if t, err := time.ParseInLocation(dateFormat, v, from.Location()); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
+
+// dueSuffix returns the text displayed after an item with a due date. Overdue
+// and due-today items that aren't done are highlighted.
+func dueSuffix(item *Item) string {
+	if item.Due.IsZero() {
+		return ""
+	}
+
+	s := fmt.Sprintf("(due %s)", item.Due.Format(dateFormat))
+	if item.Done {
+		return " " + s
+	}
+
+	today := day(now())
+	switch due := day(item.Due); {
+	case due.Before(today):
+		s = overdueFormat.Format(s)
+	case due.Equal(today):
+		s = dueTodayFormat.Format(s)
+	}
+	return " " + s
+}
+
+// SetDue sets the due date of an item. The last element of the provided path
+// is when the item is due (see parseDue), or clearValue to remove the item's
+// due date.
+func (tl *List) SetDue(output command.Output, data *command.Data) error {
+	_, item, when, err := tl.splitValue(data.StringList(pathArg))
+	if err != nil {
+		return output.Stderrf("%v\n", err)
+	}
+
+	var due time.Time
+	if when != clearValue {
+		if due, err = parseDue(when, now()); err != nil {
+			return output.Stderrf("%v\n", err)
+		}
+	}
+
+	if item.Due.Equal(due) {
+		return nil
+	}
+	item.Due = due
+	item.touch()
+	tl.changed = true
+	return nil
+}
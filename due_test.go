@@ -0,0 +1,171 @@
+package todo
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDue(t *testing.T) {
+	// testTime is a Friday.
+	for _, test := range []struct {
+		name    string
+		s       string
+		want    time.Time
+		wantErr string
+	}{
+		{
+			name: "today",
+			s:    "today",
+			want: time.Date(2023, time.February, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "tomorrow",
+			s:    "Tomorrow",
+			want: time.Date(2023, time.February, 4, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "weekday later this week",
+			s:    "sat",
+			want: time.Date(2023, time.February, 4, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "weekday next week",
+			s:    "wednesday",
+			want: time.Date(2023, time.February, 8, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "same weekday is today",
+			s:    "fri",
+			want: time.Date(2023, time.February, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "relative days",
+			s:    "+3d",
+			want: time.Date(2023, time.February, 6, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "relative weeks",
+			s:    "+2w",
+			want: time.Date(2023, time.February, 17, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "absolute date",
+			s:    "2023-03-15",
+			want: time.Date(2023, time.March, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "date without year later this year",
+			s:    "12/25",
+			want: time.Date(2023, time.December, 25, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "date without year next year",
+			s:    "01/15",
+			want: time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "invalid date",
+			s:       "someday",
+			wantErr: `invalid due date "someday"`,
+		},
+		{
+			name:    "invalid relative unit",
+			s:       "+3y",
+			wantErr: `invalid due date "+3y"`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseDue(test.s, testTime)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("parseDue(%q) returned error (%v); want (%v)", test.s, err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseDue(%q) returned error (%v); want nil", test.s, err)
+			}
+			if !got.Equal(test.want) {
+				t.Errorf("parseDue(%q) returned %v; want %v", test.s, got, test.want)
+			}
+		})
+	}
+}
+
+func TestParsePast(t *testing.T) {
+	// testTime is a Friday.
+	for _, test := range []struct {
+		name    string
+		s       string
+		want    time.Time
+		wantErr string
+	}{
+		{
+			name: "today",
+			s:    "today",
+			want: time.Date(2023, time.February, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "yesterday",
+			s:    "Yesterday",
+			want: time.Date(2023, time.February, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "weekday earlier this week",
+			s:    "mon",
+			want: time.Date(2023, time.January, 30, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "weekday last week",
+			s:    "saturday",
+			want: time.Date(2023, time.January, 28, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "same weekday is today",
+			s:    "fri",
+			want: time.Date(2023, time.February, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "days ago",
+			s:    "7d",
+			want: time.Date(2023, time.January, 27, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "weeks ago",
+			s:    "2w",
+			want: time.Date(2023, time.January, 20, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "absolute date",
+			s:    "2023-01-15",
+			want: time.Date(2023, time.January, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "invalid date",
+			s:       "last week",
+			wantErr: `invalid date "last week"`,
+		},
+		{
+			name:    "future offsets are invalid",
+			s:       "+3d",
+			wantErr: `invalid date "+3d"`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parsePast(test.s, testTime)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("parsePast(%q) returned error (%v); want (%v)", test.s, err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePast(%q) returned error (%v); want nil", test.s, err)
+			}
+			if !got.Equal(test.want) {
+				t.Errorf("parsePast(%q) returned %v; want %v", test.s, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package todo
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/leep-frog/command"
+	"github.com/leep-frog/command/color"
+)
+
+const (
+	outputFormatFlag = "format"
+
+	formatText = "text"
+	formatJSON = "json"
+	formatYAML = "yaml"
+	formatCSV  = "csv"
+	formatTSV  = "tsv"
+)
+
+var (
+	outputFormats = []string{formatText, formatJSON, formatYAML, formatCSV, formatTSV}
+
+	csvHeader = []string{"path", "done", "priority", "due", "tags", "note", "created", "updated"}
+)
+
+// listNode is the machine-readable representation of an item.
+type listNode struct {
+	Name     string        `json:"name"`
+	Format   *color.Format `json:"format,omitempty"`
+	Done     bool          `json:"done"`
+	Priority string        `json:"priority,omitempty"`
+	Due      string        `json:"due,omitempty"`
+	Repeat   string        `json:"repeat,omitempty"`
+	Tags     []string      `json:"tags,omitempty"`
+	Note     string        `json:"note,omitempty"`
+	Created  string        `json:"created,omitempty"`
+	Updated  string        `json:"updated,omitempty"`
+	Items    []*listNode   `json:"items,omitempty"`
+}
+
+// validateOutputFormat returns an error if the provided output format isn't
+// supported.
+func validateOutputFormat(format string) error {
+	for _, f := range outputFormats {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid format %q; must be one of [%s]", format, strings.Join(outputFormats, ", "))
+}
+
+// formatTime returns t in the provided layout, or an empty string if t is zero.
+func formatTime(t time.Time, layout string) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(layout)
+}
+
+func newListNode(name string, item *Item) *listNode {
+	return &listNode{
+		Name:     name,
+		Done:     item.Done,
+		Priority: item.Priority,
+		Due:      formatTime(item.Due, dateFormat),
+		Repeat:   item.Repeat,
+		Tags:     item.Tags,
+		Note:     item.Note,
+		Created:  formatTime(item.Created, time.RFC3339),
+		Updated:  formatTime(item.Updated, time.RFC3339),
+	}
+}
+
+// listNodes returns the items that ListItems displays with the provided
+// options.
+func (tl *List) listNodes(opts *listOptions) []*listNode {
+	nodes := []*listNode{}
+	for _, p := range sortedItemKeys(tl.Items, tl.Order, opts.sort) {
+		item := tl.Items[p]
+		subOpts, ok := opts.filter(item)
+		if !ok {
+			continue
+		}
+		n := newListNode(p, item)
+		n.Format = tl.PrimaryFormats[p]
+		n.Items = subNodes(item, subOpts)
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+// subNodes returns the sub-items of parent that ListItems displays with the
+// provided options.
+func subNodes(parent *Item, opts *listOptions) []*listNode {
+	var nodes []*listNode
+	for _, k := range sortedItemKeys(parent.Items, parent.Order, opts.sort) {
+		item := parent.Items[k]
+		if item.Done && opts.hideDone {
+			continue
+		}
+		subOpts, ok := opts.filter(item)
+		if !ok {
+			continue
+		}
+		n := newListNode(k, item)
+		n.Items = subNodes(item, subOpts)
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+// writeFormatted outputs the list in the provided machine-readable format.
+func (tl *List) writeFormatted(output command.Output, format string, opts *listOptions) error {
+	nodes := tl.listNodes(opts)
+	switch format {
+	case formatJSON:
+		b, err := json.MarshalIndent(nodes, "", "  ")
+		if err != nil {
+			return output.Stderrf("failed to marshal todo list json: %v\n", err)
+		}
+		output.Stdoutln(string(b))
+	case formatYAML:
+		var b strings.Builder
+		if err := writeYAML(&b, nodes, ""); err != nil {
+			return output.Stderrf("failed to marshal todo list yaml: %v\n", err)
+		}
+		output.Stdout(b.String())
+	case formatCSV, formatTSV:
+		var b strings.Builder
+		w := csv.NewWriter(&b)
+		if format == formatTSV {
+			w.Comma = '\t'
+		}
+		w.Write(csvHeader)
+		writeRecords(w, nil, nodes)
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return output.Stderrf("failed to write todo list %s: %v\n", format, err)
+		}
+		output.Stdout(b.String())
+	}
+	return nil
+}
+
+// writeRecords writes a row for each of the nodes (and their sub-items).
+func writeRecords(w *csv.Writer, path []string, nodes []*listNode) {
+	for _, n := range nodes {
+		p := append(append([]string{}, path...), n.Name)
+		w.Write([]string{
+			strings.Join(p, pathSeparator),
+			strconv.FormatBool(n.Done),
+			n.Priority,
+			n.Due,
+			strings.Join(n.Tags, " "),
+			n.Note,
+			n.Created,
+			n.Updated,
+		})
+		writeRecords(w, p, n.Items)
+	}
+}
+
+// yamlString returns s as a double-quoted YAML scalar. JSON strings are valid
+// YAML, so this avoids any ambiguity with YAML's plain scalars.
+func yamlString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// writeYAML writes nodes as a YAML sequence. Formats are written as JSON flow
+// mappings (which are also valid YAML) since their structure is owned by the
+// color package.
+func writeYAML(b *strings.Builder, nodes []*listNode, indent string) error {
+	if len(nodes) == 0 {
+		b.WriteString("[]\n")
+		return nil
+	}
+	for _, n := range nodes {
+		fmt.Fprintf(b, "%s- name: %s\n", indent, yamlString(n.Name))
+		field := indent + "  "
+		if n.Format != nil {
+			f, err := json.Marshal(n.Format)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(b, "%sformat: %s\n", field, f)
+		}
+		fmt.Fprintf(b, "%sdone: %t\n", field, n.Done)
+		scalar := func(key, value string) {
+			if value != "" {
+				fmt.Fprintf(b, "%s%s: %s\n", field, key, yamlString(value))
+			}
+		}
+		scalar("priority", n.Priority)
+		scalar("due", n.Due)
+		scalar("repeat", n.Repeat)
+		if len(n.Tags) > 0 {
+			fmt.Fprintf(b, "%stags:\n", field)
+			for _, t := range n.Tags {
+				fmt.Fprintf(b, "%s  - %s\n", field, yamlString(t))
+			}
+		}
+		scalar("note", n.Note)
+		scalar("created", n.Created)
+		scalar("updated", n.Updated)
+		if len(n.Items) > 0 {
+			fmt.Fprintf(b, "%sitems:\n", field)
+			if err := writeYAML(b, n.Items, field+"  "); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package todo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/leep-frog/command"
+)
+
+const (
+	// maxHistory is the maximum number of operations that can be undone.
+	maxHistory = 25
+
+	countArg  = "n"
+	countDesc = "Number of operations"
+)
+
+// historyEntry is a state of the list in its undo or redo history. The
+// archive and event log can grow much larger than the rest of the list, so
+// rather than a copy of them, an entry contains the changes that turn the
+// archive and log of the state that replaces it back into this state's.
+type historyEntry struct {
+	// List is the snapshot of the list (see snapshot).
+	List json.RawMessage
+	// Archive is the change to apply to the archive when restoring the entry.
+	Archive *splice[*ArchivedItem] `json:",omitempty"`
+	// Log is the change to apply to the log when restoring the entry.
+	Log *splice[*Event] `json:",omitempty"`
+}
+
+// splice is a change to the end of a slice: the Removed elements before the
+// last Keep elements of the slice are replaced with Added.
+type splice[T any] struct {
+	Keep    int `json:",omitempty"`
+	Removed int `json:",omitempty"`
+	Added   []T `json:",omitempty"`
+}
+
+// diffSlices returns the splice that changes from into to (or nil if they
+// contain the same elements). Elements are compared by identity, and only
+// the elements between the unchanged start and end of the slices are
+// included.
+func diffSlices[T comparable](from, to []T) *splice[T] {
+	prefix := 0
+	for prefix < len(from) && prefix < len(to) && from[prefix] == to[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(from)-prefix && suffix < len(to)-prefix && from[len(from)-1-suffix] == to[len(to)-1-suffix] {
+		suffix++
+	}
+	if prefix+suffix == len(from) && prefix+suffix == len(to) {
+		return nil
+	}
+	return &splice[T]{
+		Keep:    suffix,
+		Removed: len(from) - prefix - suffix,
+		Added:   to[prefix : len(to)-suffix],
+	}
+}
+
+// removeAppended returns the splice that removes the elements that were
+// appended to s since it was prev (or nil if none were). Elements that were
+// removed from the start of prev in the meantime (such as events older than
+// logRetention) aren't restored by it.
+func removeAppended[T comparable](s, prev []T) *splice[T] {
+	old := map[T]bool{}
+	for _, v := range prev {
+		old[v] = true
+	}
+	n := 0
+	for n < len(s) && !old[s[len(s)-1-n]] {
+		n++
+	}
+	if n == 0 {
+		return nil
+	}
+	return &splice[T]{Removed: n}
+}
+
+// apply returns the result of applying the splice to s.
+func (sp *splice[T]) apply(s []T) ([]T, error) {
+	if sp == nil {
+		return s, nil
+	}
+	end := len(s) - sp.Keep
+	start := end - sp.Removed
+	if start < 0 || end > len(s) {
+		return nil, fmt.Errorf("can't remove %d elements before the last %d of %d", sp.Removed, sp.Keep, len(s))
+	}
+	updated := append(append([]T{}, s[:start]...), sp.Added...)
+	return append(updated, s[end:]...), nil
+}
+
+// snapshot returns the JSON representation of the list, excluding its
+// history, archive, and event log (see historyEntry).
+func (tl *List) snapshot() (json.RawMessage, error) {
+	cp := *tl
+	cp.UndoStack, cp.RedoStack = nil, nil
+	cp.Archive = nil
+	cp.Log = nil
+	b, err := json.Marshal(cp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot todo list: %v", err)
+	}
+	return b, nil
+}
+
+// marshal returns the JSON representation of the entry.
+func (e *historyEntry) marshal() (json.RawMessage, error) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot todo list: %v", err)
+	}
+	return b, nil
+}
+
+// restore replaces the contents of the list with the provided history entry
+// while preserving the list's history.
+func (tl *List) restore(raw json.RawMessage) error {
+	// Entries saved by earlier versions are a snapshot of the whole list
+	// (including its archive), so they don't have a List field.
+	e := &historyEntry{}
+	probe := &struct{ List json.RawMessage }{}
+	if err := json.Unmarshal(raw, probe); err != nil {
+		return fmt.Errorf("failed to restore todo list snapshot: %v", err)
+	}
+	legacy := probe.List == nil
+	if legacy {
+		e.List = raw
+	} else if err := json.Unmarshal(raw, e); err != nil {
+		return fmt.Errorf("failed to restore todo list snapshot: %v", err)
+	}
+
+	// Snapshots taken before a migration are upgraded along with the list.
+	b, _, err := migrate(string(e.List))
+	if err != nil {
+		return fmt.Errorf("failed to restore todo list snapshot: %v", err)
+	}
+	restored := &List{}
+	if err := json.Unmarshal(b, restored); err != nil {
+		return fmt.Errorf("failed to restore todo list snapshot: %v", err)
+	}
+	if !legacy {
+		if restored.Archive, err = e.Archive.apply(tl.Archive); err != nil {
+			return fmt.Errorf("failed to restore todo list archive: %v", err)
+		}
+	}
+	if restored.Log, err = e.Log.apply(tl.Log); err != nil {
+		return fmt.Errorf("failed to restore todo list log: %v", err)
+	}
+	restored.UndoStack, restored.RedoStack = tl.UndoStack, tl.RedoStack
+	restored.changed = true
+	restored.name = tl.name
+	*tl = *restored
+	return nil
+}
+
+// recorded wraps an executor that modifies the list so that the list's prior
+// state is added to the undo history whenever the executor changes it.
+func (tl *List) recorded(f func(command.Output, *command.Data) error) func(command.Output, *command.Data) error {
+	return func(output command.Output, data *command.Data) error {
+		before, err := tl.snapshot()
+		if err != nil {
+			return output.Stderrf("%v\n", err)
+		}
+		// Executors may modify the archive and log in place.
+		archive := append([]*ArchivedItem{}, tl.Archive...)
+		log := append([]*Event{}, tl.Log...)
+
+		if err := f(output, data); err != nil {
+			return err
+		}
+
+		after, err := tl.snapshot()
+		if err != nil {
+			return output.Stderrf("%v\n", err)
+		}
+		e := &historyEntry{
+			List:    before,
+			Archive: diffSlices(tl.Archive, archive),
+			// Undoing an operation doesn't bring back the events it pruned.
+			Log: removeAppended(tl.Log, log),
+		}
+		if !bytes.Equal(before, after) || e.Archive != nil || e.Log != nil {
+			entry, err := e.marshal()
+			if err != nil {
+				return output.Stderrf("%v\n", err)
+			}
+			tl.UndoStack = append(tl.UndoStack, entry)
+			if len(tl.UndoStack) > maxHistory {
+				tl.UndoStack = tl.UndoStack[len(tl.UndoStack)-maxHistory:]
+			}
+			tl.RedoStack = nil
+		}
+		return nil
+	}
+}
+
+// Undo reverts the most recent operations.
+func (tl *List) Undo(output command.Output, data *command.Data) error {
+	return tl.replay(output, data, &tl.UndoStack, &tl.RedoStack, "undo")
+}
+
+// Redo reapplies the most recently undone operations.
+func (tl *List) Redo(output command.Output, data *command.Data) error {
+	return tl.replay(output, data, &tl.RedoStack, &tl.UndoStack, "redo")
+}
+
+// replay pops entries from one stack, restoring each of them and pushing the
+// state they replaced onto the other stack.
+func (tl *List) replay(output command.Output, data *command.Data, from, to *[]json.RawMessage, verb string) error {
+	n := 1
+	if data.Has(countArg) {
+		n = data.Int(countArg)
+	}
+	if n <= 0 {
+		return output.Stderr("number of operations must be positive\n")
+	}
+	if len(*from) == 0 {
+		return output.Stderrf("nothing to %s\n", verb)
+	}
+	if n > len(*from) {
+		n = len(*from)
+	}
+
+	for i := 0; i < n; i++ {
+		cur, err := tl.snapshot()
+		if err != nil {
+			return output.Stderrf("%v\n", err)
+		}
+		archive, log := tl.Archive, tl.Log
+		if err := tl.restore((*from)[len(*from)-1]); err != nil {
+			return output.Stderrf("%v\n", err)
+		}
+		entry, err := (&historyEntry{
+			List:    cur,
+			Archive: diffSlices(tl.Archive, archive),
+			Log:     diffSlices(tl.Log, log),
+		}).marshal()
+		if err != nil {
+			return output.Stderrf("%v\n", err)
+		}
+		*from = (*from)[:len(*from)-1]
+		*to = append(*to, entry)
+	}
+	return nil
+}
@@ -0,0 +1,283 @@
+package todo
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/leep-frog/command"
+)
+
+const (
+	icsDateFormat     = "20060102"
+	icsDateTimeFormat = "20060102T150405Z"
+
+	// icsLineLength is the maximum number of octets in a content line.
+	icsLineLength = 75
+
+	icsCompleted   = "COMPLETED"
+	icsNeedsAction = "NEEDS-ACTION"
+)
+
+var (
+	icsEscaper   = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+	icsUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+)
+
+// icsPriority returns the iCalendar priority (1 is the highest and 9 the
+// lowest) of a priority.
+func icsPriority(p string) int {
+	return 2*priorityRank(p) + 1
+}
+
+// parseICSPriority returns the priority for an iCalendar priority. Undefined
+// priorities (0) return an empty string.
+func parseICSPriority(v string) (string, error) {
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 || n > 9 {
+		return "", fmt.Errorf("invalid priority %q", v)
+	}
+	if n == 0 {
+		return "", nil
+	}
+	if i := (n - 1) / 2; i < len(priorities) {
+		return priorities[i], nil
+	}
+	return priorities[len(priorities)-1], nil
+}
+
+// icsUID returns a unique identifier for the item at path that is stable
+// across exports.
+func icsUID(path []string) string {
+	return fmt.Sprintf("%x@td", sha1.Sum([]byte(strings.Join(path, "\x00"))))
+}
+
+// foldICSLine splits a content line into lines of at most icsLineLength
+// octets, as required by RFC 5545.
+func foldICSLine(line string) string {
+	var b strings.Builder
+	limit := icsLineLength
+	for len(line) > limit {
+		i := limit
+		for i > 0 && !utf8.RuneStart(line[i]) {
+			i--
+		}
+		b.WriteString(line[:i] + "\r\n ")
+		line = line[i:]
+		// Continuation lines start with a space.
+		limit = icsLineLength - 1
+	}
+	b.WriteString(line + "\r\n")
+	return b.String()
+}
+
+// ExportICS outputs the list as an iCalendar (RFC 5545) calendar.
+func (tl *List) ExportICS(output command.Output, data *command.Data) error {
+	output.Stdout(tl.ics())
+	return nil
+}
+
+// ics returns the list as an iCalendar calendar with a VTODO for each
+// sub-item. A VTODO's summary is the item's path below its primary item and
+// its categories are the primary item followed by the item's tags.
+func (tl *List) ics() string {
+	var b strings.Builder
+	stamp := now().UTC().Format(icsDateTimeFormat)
+	write := func(line string) {
+		b.WriteString(foldICSLine(line))
+	}
+
+	write("BEGIN:VCALENDAR")
+	write("VERSION:2.0")
+	write("PRODID:-//leep-frog//td//EN")
+	var writeItems func(path []string, parent *Item)
+	writeItems = func(path []string, parent *Item) {
+		for _, k := range orderedKeys(parent.Items, parent.Order) {
+			item := parent.Items[k]
+			p := append(append([]string{}, path...), k)
+
+			write("BEGIN:VTODO")
+			write("UID:" + icsUID(p))
+			write("DTSTAMP:" + stamp)
+			write("SUMMARY:" + icsEscaper.Replace(strings.Join(p[1:], pathSeparator)))
+			categories := []string{icsEscaper.Replace(p[0])}
+			for _, t := range item.Tags {
+				categories = append(categories, icsEscaper.Replace(t))
+			}
+			write("CATEGORIES:" + strings.Join(categories, ","))
+			if item.Done {
+				write("STATUS:" + icsCompleted)
+			} else {
+				write("STATUS:" + icsNeedsAction)
+			}
+			if !item.Due.IsZero() {
+				write("DUE;VALUE=DATE:" + item.Due.Format(icsDateFormat))
+			}
+			if item.Priority != "" {
+				write(fmt.Sprintf("PRIORITY:%d", icsPriority(item.Priority)))
+			}
+			if item.Note != "" {
+				write("DESCRIPTION:" + icsEscaper.Replace(item.Note))
+			}
+			write("END:VTODO")
+
+			writeItems(p, item)
+		}
+	}
+	for _, p := range orderedKeys(tl.Items, tl.Order) {
+		writeItems([]string{p}, tl.Items[p])
+	}
+	write("END:VCALENDAR")
+	return b.String()
+}
+
+// ImportICS merges the VTODOs in an iCalendar file into the list.
+func (tl *List) ImportICS(output command.Output, data *command.Data) error {
+	contents, err := readImportFile(data)
+	if err != nil {
+		return output.Stderrf("%v\n", err)
+	}
+	items, err := parseICS(contents, now().Location())
+	if err != nil {
+		return output.Stderrf("%v\n", err)
+	}
+	tl.mergeItems(items)
+	return nil
+}
+
+// unfoldICS returns the content lines of an iCalendar file.
+func unfoldICS(contents string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(contents, "\r\n", "\n"), "\n") {
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// splitICSList splits a list of escaped text values on unescaped commas and
+// unescapes each value.
+func splitICSList(v string) []string {
+	var values []string
+	var cur strings.Builder
+	escaped := false
+	for _, r := range v {
+		switch {
+		case escaped:
+			cur.WriteRune('\\')
+			cur.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ',':
+			values = append(values, icsUnescaper.Replace(cur.String()))
+			cur.Reset()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	return append(values, icsUnescaper.Replace(cur.String()))
+}
+
+// parseICSDate parses a DATE or DATE-TIME value. Only the date is kept.
+func parseICSDate(v string, loc *time.Location) (time.Time, error) {
+	if strings.HasSuffix(v, "Z") {
+		t, err := time.Parse(icsDateTimeFormat, v)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return day(t.In(loc)), nil
+	}
+	if len(v) < len(icsDateFormat) {
+		return time.Time{}, fmt.Errorf("invalid date %q", v)
+	}
+	return time.ParseInLocation(icsDateFormat, v[:len(icsDateFormat)], loc)
+}
+
+// parseICS parses the VTODOs in an iCalendar file. A VTODO's first category is
+// its primary item and its summary is the path below it (VTODOs without
+// categories are primary items). The remaining categories are kept as tags.
+func parseICS(contents string, loc *time.Location) ([]*importedItem, error) {
+	var items []*importedItem
+	var item *Item
+	var summary string
+	var categories []string
+	// nested is the depth of components (such as alarms) within the VTODO.
+	nested := 0
+	for _, line := range unfoldICS(contents) {
+		nameAndParams, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name, _, _ := strings.Cut(nameAndParams, ";")
+		name = strings.ToUpper(name)
+
+		if name == "BEGIN" && value == "VTODO" {
+			item, summary, categories, nested = &Item{}, "", nil, 0
+			continue
+		}
+		if item == nil {
+			continue
+		}
+		if name == "BEGIN" {
+			nested++
+			continue
+		}
+		if nested > 0 {
+			if name == "END" {
+				nested--
+			}
+			continue
+		}
+
+		switch name {
+		case "END":
+			if summary == "" {
+				return nil, fmt.Errorf("VTODO %d has no SUMMARY", len(items)+1)
+			}
+			var path []string
+			if len(categories) == 0 {
+				path = []string{summary}
+			} else {
+				path = append([]string{categories[0]}, strings.Split(summary, pathSeparator)...)
+				item.Tags = categories[1:]
+			}
+			items = append(items, &importedItem{
+				path: path,
+				item: item,
+			})
+			item = nil
+		case "SUMMARY":
+			summary = icsUnescaper.Replace(value)
+		case "CATEGORIES":
+			for _, c := range splitICSList(value) {
+				if c != "" {
+					categories = append(categories, c)
+				}
+			}
+		case "STATUS":
+			item.Done = strings.ToUpper(value) == icsCompleted
+		case "DUE":
+			t, err := parseICSDate(value, loc)
+			if err != nil {
+				return nil, fmt.Errorf("VTODO %d has invalid DUE: %v", len(items)+1, err)
+			}
+			item.Due = t
+		case "PRIORITY":
+			p, err := parseICSPriority(value)
+			if err != nil {
+				return nil, fmt.Errorf("VTODO %d has invalid PRIORITY: %v", len(items)+1, err)
+			}
+			item.Priority = p
+		case "DESCRIPTION":
+			item.Note = icsUnescaper.Replace(value)
+		}
+	}
+	return items, nil
+}
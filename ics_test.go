@@ -0,0 +1,311 @@
+package todo
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leep-frog/command"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestExportICS(t *testing.T) {
+	stubNow(t, testTime)
+	l := &List{
+		Items: map[string]*Item{
+			"sleep": {},
+			"write, edit": {
+				Order: []string{"tests", "code"},
+				Items: map[string]*Item{
+					"code": {
+						Done: true,
+						Tags: []string{"@desk"},
+						Items: map[string]*Item{
+							"parser": {
+								Due:      testDue,
+								Priority: "P1",
+								Note:     "handle; commas, too\nand newlines",
+							},
+						},
+					},
+					"tests": {},
+				},
+			},
+		},
+	}
+
+	command.ExecuteTest(t, &command.ExecuteTestCase{
+		Node: l.Node(),
+		Args: []string{"export", "ics"},
+		WantStdout: strings.Join([]string{
+			"BEGIN:VCALENDAR",
+			"VERSION:2.0",
+			"PRODID:-//leep-frog//td//EN",
+			"BEGIN:VTODO",
+			"UID:" + icsUID([]string{"write, edit", "tests"}),
+			"DTSTAMP:20230203T040500Z",
+			"SUMMARY:tests",
+			`CATEGORIES:write\, edit`,
+			"STATUS:NEEDS-ACTION",
+			"END:VTODO",
+			"BEGIN:VTODO",
+			"UID:" + icsUID([]string{"write, edit", "code"}),
+			"DTSTAMP:20230203T040500Z",
+			"SUMMARY:code",
+			`CATEGORIES:write\, edit,@desk`,
+			"STATUS:COMPLETED",
+			"END:VTODO",
+			"BEGIN:VTODO",
+			"UID:" + icsUID([]string{"write, edit", "code", "parser"}),
+			"DTSTAMP:20230203T040500Z",
+			"SUMMARY:code/parser",
+			`CATEGORIES:write\, edit`,
+			"STATUS:NEEDS-ACTION",
+			"DUE;VALUE=DATE:20230210",
+			"PRIORITY:3",
+			`DESCRIPTION:handle\; commas\, too\nand newlines`,
+			"END:VTODO",
+			"END:VCALENDAR",
+			"",
+		}, "\r\n"),
+	})
+}
+
+func TestFoldICSLine(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		line string
+		want string
+	}{
+		{
+			name: "short line",
+			line: "SUMMARY:code",
+			want: "SUMMARY:code\r\n",
+		},
+		{
+			name: "long line",
+			line: "DESCRIPTION:" + strings.Repeat("a", 150),
+			want: "DESCRIPTION:" + strings.Repeat("a", 63) + "\r\n " + strings.Repeat("a", 74) + "\r\n " + strings.Repeat("a", 13) + "\r\n",
+		},
+		{
+			name: "doesn't split multi-byte characters",
+			line: "SUMMARY:" + strings.Repeat("a", 66) + "éé",
+			want: "SUMMARY:" + strings.Repeat("a", 66) + "\r\n éé\r\n",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if diff := cmp.Diff(test.want, foldICSLine(test.line)); diff != "" {
+				t.Errorf("foldICSLine(%q) returned diff (-want, +got):\n%s", test.line, diff)
+			}
+		})
+	}
+}
+
+func TestImportICS(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		l          *List
+		contents   []string
+		want       *List
+		wantStderr string
+	}{
+		{
+			name: "imports VTODOs",
+			contents: []string{
+				"BEGIN:VCALENDAR",
+				"VERSION:2.0",
+				"BEGIN:VEVENT",
+				"SUMMARY:not a todo",
+				"END:VEVENT",
+				"BEGIN:VTODO",
+				"SUMMARY:code/pars",
+				" er",
+				`CATEGORIES:write\, edit,@desk`,
+				"CATEGORIES:@laptop",
+				"STATUS:COMPLETED",
+				"DUE:20230210T230000Z",
+				"PRIORITY:4",
+				`DESCRIPTION;LANGUAGE=en:handle\; commas\, too\nand newlines`,
+				"BEGIN:VALARM",
+				"DESCRIPTION:alarm",
+				"END:VALARM",
+				"END:VTODO",
+				"BEGIN:VTODO",
+				"SUMMARY:sleep",
+				"STATUS:NEEDS-ACTION",
+				"DUE;VALUE=DATE:20230210",
+				"PRIORITY:0",
+				"END:VTODO",
+				"END:VCALENDAR",
+			},
+			want: &List{
+				changed: true,
+				Order:   []string{"write, edit", "sleep"},
+				Items: map[string]*Item{
+					"sleep": {
+						Created: testTime,
+						Updated: testTime,
+						Due:     testDue,
+					},
+					"write, edit": {
+						Created: testTime,
+						Updated: testTime,
+						Order:   []string{"code"},
+						Items: map[string]*Item{
+							"code": {
+								Created: testTime,
+								Updated: testTime,
+								Order:   []string{"parser"},
+								Items: map[string]*Item{
+									"parser": {
+										Done:     true,
+										Created:  testTime,
+										Updated:  testTime,
+										Due:      testDue,
+										Priority: "P1",
+										Note:     "handle; commas, too\nand newlines",
+										Tags:     []string{"@desk", "@laptop"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "merges into existing items",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {Done: true},
+						},
+					},
+				},
+			},
+			contents: []string{
+				"BEGIN:VCALENDAR",
+				"BEGIN:VTODO",
+				"SUMMARY:code",
+				"CATEGORIES:write",
+				"STATUS:IN-PROCESS",
+				"END:VTODO",
+				"END:VCALENDAR",
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {Updated: testTime},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "errors on missing summary",
+			contents: []string{
+				"BEGIN:VTODO",
+				"CATEGORIES:write",
+				"END:VTODO",
+			},
+			wantStderr: "VTODO 1 has no SUMMARY\n",
+		},
+		{
+			name: "errors on invalid due date",
+			contents: []string{
+				"BEGIN:VTODO",
+				"SUMMARY:code",
+				"DUE:soon",
+				"END:VTODO",
+			},
+			wantStderr: "VTODO 1 has invalid DUE: invalid date \"soon\"\n",
+		},
+		{
+			name: "errors on invalid priority",
+			contents: []string{
+				"BEGIN:VTODO",
+				"SUMMARY:code",
+				"PRIORITY:high",
+				"END:VTODO",
+			},
+			wantStderr: "VTODO 1 has invalid PRIORITY: invalid priority \"high\"\n",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			stubNow(t, testTime)
+			if test.l == nil {
+				test.l = &List{}
+			}
+			f := writeImportFile(t, "todo.ics", strings.Join(test.contents, "\r\n"))
+
+			executeTest(t, test.l, []string{"import", "ics", f}, &command.Data{
+				Values: map[string]interface{}{
+					fileArg: f,
+				},
+			}, nil, test.wantStderr)
+			command.ChangeTest(t, test.want, test.l, cmp.AllowUnexported(List{}), ignoreHistory)
+		})
+	}
+}
+
+func TestICSRoundTrip(t *testing.T) {
+	stubNow(t, testTime)
+	want := &List{
+		Order: []string{"write", "sleep"},
+		Items: map[string]*Item{
+			"write": {
+				Created: testTime,
+				Updated: testTime,
+				Order:   []string{"tests", "code"},
+				Items: map[string]*Item{
+					"code": {
+						Done:     true,
+						Priority: "P3",
+						Created:  testTime,
+						Updated:  testTime,
+						Order:    []string{"parser"},
+						Items: map[string]*Item{
+							"parser": {
+								Created: testTime,
+								Updated: testTime,
+								Due:     testDue,
+								Note:    strings.Repeat("a long note, ", 10),
+								Tags:    []string{"@desk", "@laptop"},
+							},
+						},
+					},
+					"tests": {
+						Created: testTime,
+						Updated: testTime,
+					},
+				},
+			},
+			"sleep": {
+				Created: testTime,
+				Updated: testTime,
+				Order:   []string{"nap"},
+				Items: map[string]*Item{
+					"nap": {
+						Created: testTime,
+						Updated: testTime,
+					},
+				},
+			},
+		},
+	}
+
+	items, err := parseICS(want.ics(), time.UTC)
+	if err != nil {
+		t.Fatalf("parseICS() returned error: %v", err)
+	}
+	got := &List{}
+	got.mergeItems(items)
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(List{})); diff != "" {
+		t.Errorf("iCalendar round trip produced diff (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,190 @@
+package todo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	// now returns the current time. It is a variable so tests can stub it.
+	now = time.Now
+)
+
+// Item is a single entry in a todo list. Items can be nested to any depth.
+type Item struct {
+	// Done is whether or not the item has been completed.
+	Done bool `json:",omitempty"`
+	// Items are the sub-items of this item.
+	Items map[string]*Item `json:",omitempty"`
+	// Order is the manual display order of the sub-items.
+	Order []string `json:",omitempty"`
+
+	// Created is when the item was added to the list.
+	Created time.Time
+	// Updated is when the item was last modified.
+	Updated time.Time
+	// Note is free-form text describing the item.
+	Note string `json:",omitempty"`
+	// Due is when the item needs to be done by.
+	Due time.Time
+	// Repeat is the item's recurrence rule (see parseRepeat). Completing a
+	// recurring item reopens it with its next due date.
+	Repeat string `json:",omitempty"`
+	// Priority is the item's priority (P0 through P3).
+	Priority string `json:",omitempty"`
+	// BlockedBy are the paths of the items that need to be done before this
+	// item can be done.
+	BlockedBy [][]string `json:",omitempty"`
+	// Sessions are the periods of time spent working on the item.
+	Sessions []*Session `json:",omitempty"`
+	// Pomodoros is the number of pomodoros completed for the item.
+	Pomodoros int `json:",omitempty"`
+	// Tags are labels (such as contexts) used to filter items across primaries.
+	Tags []string `json:",omitempty"`
+}
+
+// touch updates the item's modification time.
+func (i *Item) touch() {
+	i.Updated = now()
+}
+
+// count returns the number of items in the tree rooted at this item.
+func (i *Item) count() int {
+	n := 1
+	for _, sub := range i.Items {
+		n += sub.count()
+	}
+	return n
+}
+
+// sortedKeys returns the keys of the provided items in alphabetical order.
+func sortedKeys(items map[string]*Item) []string {
+	keys := make([]string, 0, len(items))
+	for k := range items {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// pathString returns a quoted, comma-separated representation of path.
+func pathString(path []string) string {
+	qs := make([]string, 0, len(path))
+	for _, p := range path {
+		qs = append(qs, fmt.Sprintf("%q", p))
+	}
+	return strings.Join(qs, ", ")
+}
+
+// get returns the item at the provided path. If any part of the path
+// does not exist, an error naming the missing prefix is returned.
+func (tl *List) get(path []string) (*Item, error) {
+	items := tl.Items
+	var item *Item
+	for i, p := range path {
+		var ok bool
+		if item, ok = items[p]; !ok {
+			return nil, fmt.Errorf("item %s does not exist", pathString(path[:i+1]))
+		}
+		items = item.Items
+	}
+	return item, nil
+}
+
+// children returns the sub-items of the item at the provided path (or the
+// primary items if path is empty). Nil is returned if the path does not exist.
+func (tl *List) children(path []string) map[string]*Item {
+	if len(path) == 0 {
+		return tl.Items
+	}
+	item, err := tl.get(path)
+	if err != nil {
+		return nil
+	}
+	return item.Items
+}
+
+// splitPath splits path into its longest prefix that references an existing
+// item and the remaining elements.
+func (tl *List) splitPath(path []string) ([]string, []string) {
+	items := tl.Items
+	for i, p := range path {
+		item, ok := items[p]
+		if !ok {
+			return path[:i], path[i:]
+		}
+		items = item.Items
+	}
+	return path, nil
+}
+
+// valueSeparator optionally separates the path of an item from the values
+// provided for it (e.g. "note write code -- tests are flaky").
+const valueSeparator = "--"
+
+// cutSeparator splits args around the first valueSeparator. The returned
+// bool is whether args contains the separator.
+func cutSeparator(args []string) ([]string, []string, bool) {
+	for i, a := range args {
+		if a == valueSeparator {
+			return args[:i], args[i+1:], true
+		}
+	}
+	return args, nil, false
+}
+
+// splitValues splits args into the path of an existing item and the values
+// provided for it. If args contains a valueSeparator, the path is everything
+// before it. Otherwise, the path is the longest prefix of args that
+// references an existing item (see splitPath), so the separator is only
+// needed when a value is also the name of a sub-item.
+func (tl *List) splitValues(args []string) ([]string, *Item, []string, error) {
+	path, values, ok := cutSeparator(args)
+	if !ok {
+		path, values = tl.splitPath(args)
+		if len(path) == 0 {
+			return nil, nil, nil, fmt.Errorf("item %s does not exist", pathString(values[:1]))
+		}
+	}
+	if len(path) == 0 {
+		return nil, nil, nil, fmt.Errorf("no item provided before %q", valueSeparator)
+	}
+	item, err := tl.get(path)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return path, item, values, nil
+}
+
+// clearValue is the value that removes a single-valued field of an item (e.g.
+// "due write code none").
+const clearValue = "none"
+
+// splitValue splits args into the path of an existing item and the single
+// value provided for it, which is always the last element of args.
+func (tl *List) splitValue(args []string) ([]string, *Item, string, error) {
+	if len(args) < 2 {
+		return nil, nil, "", fmt.Errorf("expected the path of an item followed by a value; got %v", args)
+	}
+	path, value := args[:len(args)-1], args[len(args)-1]
+	item, err := tl.get(path)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return path, item, value, nil
+}
+
+// isPrefix returns whether prefix is a prefix of path.
+func isPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i, p := range prefix {
+		if path[i] != p {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,110 @@
+package todo
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/leep-frog/command"
+)
+
+var (
+	headingRegex   = regexp.MustCompile(`^#+\s+(.*?)\s*$`)
+	checklistRegex = regexp.MustCompile(`^(\s*)[-*+]\s+\[([ xX])\]\s+(.*?)\s*$`)
+)
+
+// ExportMarkdown outputs the list as a GitHub-flavored Markdown document.
+func (tl *List) ExportMarkdown(output command.Output, data *command.Data) error {
+	output.Stdout(tl.markdown())
+	return nil
+}
+
+// markdown returns the list as a GitHub-flavored Markdown document with a
+// heading for each primary item and a checklist of its sub-items.
+func (tl *List) markdown() string {
+	var b strings.Builder
+	for i, p := range orderedKeys(tl.Items, tl.Order) {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "# %s\n", p)
+		if len(tl.Items[p].Items) > 0 {
+			b.WriteString("\n")
+			writeMarkdownItems(&b, tl.Items[p], "")
+		}
+	}
+	return b.String()
+}
+
+func writeMarkdownItems(b *strings.Builder, parent *Item, indent string) {
+	for _, k := range orderedKeys(parent.Items, parent.Order) {
+		item := parent.Items[k]
+		box := openBox
+		if item.Done {
+			box = doneBox
+		}
+		fmt.Fprintf(b, "%s- %s %s\n", indent, box, k)
+		writeMarkdownItems(b, item, indent+"  ")
+	}
+}
+
+// ImportMarkdown merges the items in a Markdown checklist (as produced by
+// ExportMarkdown) into the list.
+func (tl *List) ImportMarkdown(output command.Output, data *command.Data) error {
+	contents, err := readImportFile(data)
+	if err != nil {
+		return output.Stderrf("%v\n", err)
+	}
+	items, err := parseMarkdown(contents)
+	if err != nil {
+		return output.Stderrf("%v\n", err)
+	}
+	tl.mergeItems(items)
+	return nil
+}
+
+// parseMarkdown parses a Markdown checklist. Headings are primary items and
+// checklist entries are nested under the preceding heading according to their
+// indentation. All other lines are ignored.
+func parseMarkdown(contents string) ([]*importedItem, error) {
+	type level struct {
+		indent int
+		name   string
+	}
+	var items []*importedItem
+	var primary string
+	var parents []level
+	for i, line := range strings.Split(contents, "\n") {
+		if m := headingRegex.FindStringSubmatch(line); m != nil && m[1] != "" {
+			primary = m[1]
+			parents = nil
+			items = append(items, &importedItem{path: []string{primary}})
+			continue
+		}
+
+		m := checklistRegex.FindStringSubmatch(line)
+		if m == nil || m[3] == "" {
+			continue
+		}
+		if primary == "" {
+			return nil, fmt.Errorf("line %d: checklist item must be under a heading", i+1)
+		}
+
+		indent := len(strings.ReplaceAll(m[1], "\t", "    "))
+		for len(parents) > 0 && parents[len(parents)-1].indent >= indent {
+			parents = parents[:len(parents)-1]
+		}
+		path := []string{primary}
+		for _, p := range parents {
+			path = append(path, p.name)
+		}
+		path = append(path, m[3])
+		parents = append(parents, level{indent, m[3]})
+
+		items = append(items, &importedItem{
+			path: path,
+			item: &Item{Done: m[2] != " "},
+		})
+	}
+	return items, nil
+}
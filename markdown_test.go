@@ -0,0 +1,291 @@
+package todo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leep-frog/command"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// writeImportFile writes contents to a temporary file and returns its path.
+func writeImportFile(t *testing.T, name, contents string) string {
+	t.Helper()
+	f := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(f, []byte(contents), 0644); err != nil {
+		t.Fatalf("failed to write import file: %v", err)
+	}
+	return f
+}
+
+func TestExportMarkdown(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		l    *List
+		want []string
+	}{
+		{
+			name: "exports empty list",
+		},
+		{
+			name: "exports items",
+			l: &List{
+				Order: []string{"write", "sleep"},
+				Items: map[string]*Item{
+					"sleep": {},
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Done: true,
+								Items: map[string]*Item{
+									"parser": {Done: true},
+									"cli":    {},
+								},
+							},
+							"tests": {},
+						},
+					},
+				},
+			},
+			want: []string{
+				"# write",
+				"",
+				"- [x] code",
+				"  - [ ] cli",
+				"  - [x] parser",
+				"- [ ] tests",
+				"",
+				"# sleep",
+				"",
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if test.l == nil {
+				test.l = &List{}
+			}
+			command.ExecuteTest(t, &command.ExecuteTestCase{
+				Node:       test.l.Node(),
+				Args:       []string{"export", "md"},
+				WantStdout: strings.Join(test.want, "\n"),
+			})
+		})
+	}
+}
+
+func TestImportMarkdown(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		l          *List
+		contents   []string
+		want       *List
+		wantStderr string
+	}{
+		{
+			name: "imports into empty list",
+			contents: []string{
+				"Some intro text.",
+				"",
+				"## write",
+				"",
+				"- [x] code",
+				"  * [ ] cli",
+				"  * [X] parser",
+				"- [ ] tests",
+				"",
+				"# sleep",
+			},
+			want: &List{
+				changed: true,
+				Order:   []string{"write", "sleep"},
+				Items: map[string]*Item{
+					"sleep": {
+						Created: testTime,
+						Updated: testTime,
+					},
+					"write": {
+						Created: testTime,
+						Updated: testTime,
+						Order:   []string{"code", "tests"},
+						Items: map[string]*Item{
+							"code": {
+								Done:    true,
+								Created: testTime,
+								Updated: testTime,
+								Order:   []string{"cli", "parser"},
+								Items: map[string]*Item{
+									"cli": {
+										Created: testTime,
+										Updated: testTime,
+									},
+									"parser": {
+										Done:    true,
+										Created: testTime,
+										Updated: testTime,
+									},
+								},
+							},
+							"tests": {
+								Created: testTime,
+								Updated: testTime,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "merges into existing items",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Done: true,
+						Items: map[string]*Item{
+							"code":  {},
+							"tests": {Done: true},
+							"docs":  {},
+						},
+					},
+				},
+			},
+			contents: []string{
+				"# write",
+				"- [x] code",
+				"- [x] tests",
+				"- [ ] lint",
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Done:  true,
+						Order: []string{"code", "docs", "tests", "lint"},
+						Items: map[string]*Item{
+							"code": {
+								Done:    true,
+								Updated: testTime,
+							},
+							"docs":  {},
+							"tests": {Done: true},
+							"lint": {
+								Created: testTime,
+								Updated: testTime,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "importing existing items is a no-op",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {Done: true},
+						},
+					},
+				},
+			},
+			contents: []string{
+				"# write",
+				"- [x] code",
+			},
+		},
+		{
+			name: "errors on checklist item outside of a heading",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+			},
+			contents: []string{
+				"- [ ] code",
+				"# write",
+			},
+			wantStderr: "line 1: checklist item must be under a heading\n",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			stubNow(t, testTime)
+			if test.l == nil {
+				test.l = &List{}
+			}
+			f := writeImportFile(t, "todo.md", strings.Join(test.contents, "\n"))
+
+			executeTest(t, test.l, []string{"import", "md", f}, &command.Data{
+				Values: map[string]interface{}{
+					fileArg: f,
+				},
+			}, nil, test.wantStderr)
+			command.ChangeTest(t, test.want, test.l, cmp.AllowUnexported(List{}), ignoreHistory)
+		})
+	}
+}
+
+func TestImportMarkdownErrorsOnMissingFile(t *testing.T) {
+	f := filepath.Join(t.TempDir(), "missing.md")
+	l := &List{}
+	command.ExecuteTest(t, &command.ExecuteTestCase{
+		Node: l.Node(),
+		Args: []string{"import", "md", f},
+		WantData: &command.Data{
+			Values: map[string]interface{}{
+				fileArg: f,
+			},
+		},
+		WantStderr: fmt.Sprintf("failed to read import file: open %s: no such file or directory\n", f),
+		WantErr:    fmt.Errorf("failed to read import file: open %s: no such file or directory", f),
+	})
+}
+
+func TestMarkdownRoundTrip(t *testing.T) {
+	stubNow(t, testTime)
+	want := &List{
+		Order: []string{"write", "sleep"},
+		Items: map[string]*Item{
+			"sleep": {
+				Created: testTime,
+				Updated: testTime,
+			},
+			"write": {
+				Created: testTime,
+				Updated: testTime,
+				Order:   []string{"tests", "code"},
+				Items: map[string]*Item{
+					"code": {
+						Done:    true,
+						Created: testTime,
+						Updated: testTime,
+						Order:   []string{"parser"},
+						Items: map[string]*Item{
+							"parser": {
+								Created: testTime,
+								Updated: testTime,
+							},
+						},
+					},
+					"tests": {
+						Created: testTime,
+						Updated: testTime,
+					},
+				},
+			},
+		},
+	}
+
+	items, err := parseMarkdown(want.markdown())
+	if err != nil {
+		t.Fatalf("parseMarkdown() returned error: %v", err)
+	}
+	got := &List{}
+	got.mergeItems(items)
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(List{})); diff != "" {
+		t.Errorf("Markdown round trip produced diff (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,120 @@
+package todo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// migration upgrades the JSON representation of a list by one version.
+type migration func(map[string]interface{}) error
+
+var (
+	// migrations contains the function that upgrades each version of the
+	// list's JSON to the next version. The migration at index i upgrades a list
+	// from version i to version i+1. Lists without a version are version 0.
+	//
+	// When a change to the model would alter or drop existing data, add a
+	// migration to the end of this list and a fixture of the previous format to
+	// testdata/.
+	migrations = []migration{
+		// 0 -> 1
+		migrateTwoLayerItems,
+	}
+
+	// currentVersion is the version of the list's JSON that the model
+	// corresponds to.
+	currentVersion = len(migrations)
+)
+
+// migrate upgrades the provided list JSON to the current version.
+func migrate(jsn string) ([]byte, bool, error) {
+	d := json.NewDecoder(bytes.NewReader([]byte(jsn)))
+	d.UseNumber()
+	var raw map[string]interface{}
+	if err := d.Decode(&raw); err != nil {
+		return nil, false, err
+	}
+	// Decoding null leaves the map nil rather than failing.
+	if raw == nil {
+		return nil, false, fmt.Errorf("expected a json object; got null")
+	}
+
+	version := 0
+	if v, ok := raw["Version"]; ok {
+		n, ok := v.(json.Number)
+		if !ok {
+			return nil, false, fmt.Errorf("invalid version %v", v)
+		}
+		i, err := n.Int64()
+		if err != nil || i < 0 {
+			return nil, false, fmt.Errorf("invalid version %v", v)
+		}
+		version = int(i)
+	}
+	if version > currentVersion {
+		return nil, false, fmt.Errorf("version %d is newer than the latest supported version (%d)", version, currentVersion)
+	}
+	if version == currentVersion {
+		return []byte(jsn), false, nil
+	}
+
+	for v := version; v < currentVersion; v++ {
+		if err := migrations[v](raw); err != nil {
+			return nil, false, fmt.Errorf("failed to migrate from version %d to %d: %v", v, v+1, err)
+		}
+	}
+	raw["Version"] = currentVersion
+
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return nil, false, err
+	}
+	return b, true, nil
+}
+
+// migrateTwoLayerItems converts lists saved when items could only be two
+// layers deep into a tree of items. A secondary item's value was true while
+// the item was still open.
+func migrateTwoLayerItems(raw map[string]interface{}) error {
+	items, ok := raw["Items"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	for p, v := range items {
+		secondaries, ok := v.(map[string]interface{})
+		if !ok || !isTwoLayerPrimary(secondaries) {
+			continue
+		}
+
+		primary := map[string]interface{}{}
+		if len(secondaries) > 0 {
+			subItems := map[string]interface{}{}
+			for s, open := range secondaries {
+				subItems[s] = map[string]interface{}{
+					"Done": !open.(bool),
+				}
+			}
+			primary["Items"] = subItems
+		}
+		items[p] = primary
+	}
+	return nil
+}
+
+// isTwoLayerPrimary returns whether the JSON object of a primary item is in the
+// two-layer format, i.e. all of its values are booleans. An object that only
+// contains "Done" is treated as an item since both formats are possible.
+func isTwoLayerPrimary(obj map[string]interface{}) bool {
+	onlyDone := true
+	for k, v := range obj {
+		if _, ok := v.(bool); !ok {
+			return false
+		}
+		if k != "Done" {
+			onlyDone = false
+		}
+	}
+	return !onlyDone || len(obj) == 0
+}
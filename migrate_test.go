@@ -0,0 +1,216 @@
+package todo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leep-frog/command"
+	"github.com/leep-frog/command/color"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// migrationFixtures maps each file in testdata/ to the list it should load as.
+// Fixtures are named "v<version>_<description>.json" and every version must
+// have at least one fixture.
+var migrationFixtures = map[string]*List{
+	"v0_two_layer.json": {
+		Version: currentVersion,
+		Items: map[string]*Item{
+			"sleep": {},
+			"write": {
+				Items: map[string]*Item{
+					"code":  {Done: true},
+					"tests": {},
+				},
+			},
+		},
+		PrimaryFormats: map[string]*color.Format{
+			"write": {
+				Color:     color.Red,
+				Thickness: color.Bold,
+			},
+		},
+	},
+	"v0_tree.json": {
+		Version: currentVersion,
+		Order:   []string{"write", "sleep"},
+		Items: map[string]*Item{
+			"sleep": {
+				Done:    true,
+				Created: fixtureTime(1),
+				Updated: fixtureTime(2),
+			},
+			"write": {
+				Created: fixtureTime(1),
+				Updated: fixtureTime(1),
+				Order:   []string{"tests", "code"},
+				Items: map[string]*Item{
+					"code": {
+						Done:     true,
+						Created:  fixtureTime(1),
+						Updated:  fixtureTime(2),
+						Note:     "in go",
+						Due:      time.Date(2023, time.February, 10, 0, 0, 0, 0, time.UTC),
+						Priority: "P1",
+						Tags:     []string{"@desk"},
+					},
+					"tests": {
+						Created: fixtureTime(1),
+						Updated: fixtureTime(1),
+					},
+				},
+			},
+		},
+		PrimaryFormats: map[string]*color.Format{
+			"write": {
+				Color:     color.Red,
+				Thickness: color.Bold,
+			},
+		},
+	},
+	"v1_tree.json": {
+		Version: 1,
+		Items: map[string]*Item{
+			"write": {
+				Created: fixtureTime(1),
+				Updated: fixtureTime(1),
+				Items: map[string]*Item{
+					"code": {
+						Done:    true,
+						Created: fixtureTime(1),
+						Updated: fixtureTime(2),
+					},
+				},
+			},
+		},
+		PrimaryFormats: map[string]*color.Format{},
+	},
+}
+
+// fixtureTime returns the time used in fixtures for the provided day in
+// February 2023.
+func fixtureTime(day int) time.Time {
+	return time.Date(2023, time.February, day, 9, 0, 0, 0, time.UTC)
+}
+
+func TestMigrationFixtures(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "v*.json"))
+	if err != nil {
+		t.Fatalf("failed to list fixtures: %v", err)
+	}
+	for _, f := range files {
+		if _, ok := migrationFixtures[filepath.Base(f)]; !ok {
+			t.Errorf("fixture %s has no expected list in migrationFixtures", f)
+		}
+	}
+	for v := 0; v <= currentVersion; v++ {
+		found := false
+		for name := range migrationFixtures {
+			found = found || strings.HasPrefix(name, fmt.Sprintf("v%d_", v))
+		}
+		if !found {
+			t.Errorf("no fixture for version %d; add one to testdata/", v)
+		}
+	}
+
+	for name, want := range migrationFixtures {
+		t.Run(name, func(t *testing.T) {
+			b, err := os.ReadFile(filepath.Join("testdata", name))
+			if err != nil {
+				t.Fatalf("failed to read fixture: %v", err)
+			}
+
+			l := &List{}
+			if err := l.Load(string(b)); err != nil {
+				t.Fatalf("Load(%s) returned error: %v", name, err)
+			}
+			if diff := cmp.Diff(want, l, cmpopts.IgnoreUnexported(List{}), ignoreHistory); diff != "" {
+				t.Errorf("Load(%s) produced todo list diff (-want, +got):\n%s", name, diff)
+			}
+			if wantChanged := !strings.HasPrefix(name, fmt.Sprintf("v%d_", currentVersion)); l.Changed() != wantChanged {
+				t.Errorf("Load(%s) set Changed() to %v; want %v", name, l.Changed(), wantChanged)
+			}
+		})
+	}
+}
+
+func TestUndoMigratesSnapshots(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("testdata", "v0_tree.json"))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	l := &List{}
+	if err := l.Load(string(b)); err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+
+	command.ExecuteTest(t, &command.ExecuteTestCase{
+		Node: l.Node(),
+		Args: []string{"undo"},
+	})
+	want := &List{
+		Version: currentVersion,
+		Items: map[string]*Item{
+			"write": {
+				Created: fixtureTime(1),
+				Updated: fixtureTime(1),
+			},
+		},
+	}
+	if diff := cmp.Diff(want, l, cmpopts.IgnoreUnexported(List{}), ignoreHistory); diff != "" {
+		t.Errorf("undo produced todo list diff (-want, +got):\n%s", diff)
+	}
+}
+
+func TestLoadVersionErrors(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		json    string
+		wantErr string
+	}{
+		{
+			name:    "errors on newer version",
+			json:    fmt.Sprintf(`{"Version": %d}`, currentVersion+1),
+			wantErr: fmt.Sprintf("failed to unmarshal todo list json: version %d is newer than the latest supported version (%d)", currentVersion+1, currentVersion),
+		},
+		{
+			name:    "errors on invalid version",
+			json:    `{"Version": "one"}`,
+			wantErr: "failed to unmarshal todo list json: invalid version one",
+		},
+		{
+			name:    "errors on negative version",
+			json:    `{"Version": -1}`,
+			wantErr: "failed to unmarshal todo list json: invalid version -1",
+		},
+		{
+			name:    "errors on null",
+			json:    "null",
+			wantErr: "failed to unmarshal todo list json: expected a json object; got null",
+		},
+		{
+			name:    "errors on non-object",
+			json:    `["write"]`,
+			wantErr: "failed to unmarshal todo list json: json: cannot unmarshal array into Go value of type map[string]interface {}",
+		},
+		{
+			name:    "errors on unknown fields",
+			json:    fmt.Sprintf(`{"Version": %d, "Unknown": {}}`, currentVersion),
+			wantErr: `failed to unmarshal todo list json: json: unknown field "Unknown"`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			l := &List{}
+			err := l.Load(test.json)
+			if err == nil || err.Error() != test.wantErr {
+				t.Errorf("Load(%s) returned error %v; want %q", test.json, err, test.wantErr)
+			}
+		})
+	}
+}
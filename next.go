@@ -0,0 +1,179 @@
+package todo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/leep-frog/command"
+)
+
+const (
+	// defaultNextCount is the number of items that `next` displays by default.
+	defaultNextCount = 3
+
+	// dueTodayScore is the score of an item that is due today. Overdue items get
+	// overdueDayScore more points for each day they are overdue (up to
+	// maxOverdueDays), and items due within a week get dueSoonDayScore fewer
+	// points for each day until they are due.
+	dueTodayScore   = 45
+	overdueDayScore = 2
+	maxOverdueDays  = 10
+	dueSoonDays     = 7
+	dueSoonDayScore = 5
+
+	// ageWeekScore is the score of an item for each week it has been open, up
+	// to maxAgeScore.
+	ageWeekScore = 1
+	maxAgeScore  = 10
+
+	// blockedScore is added to the score of items that are blocked so that they
+	// are ranked after all actionable items.
+	blockedScore = -100
+)
+
+var (
+	// priorityScores are the scores of each priority.
+	priorityScores = map[string]int{
+		"P0": 40,
+		"P1": 30,
+		"P2": 20,
+		"P3": 10,
+	}
+)
+
+// Candidate is an item ranked by Rank.
+type Candidate struct {
+	// Path is the item's path.
+	Path []string
+	// Item is the ranked item.
+	Item *Item
+	// Score is how actionable the item is. Higher scores are more actionable.
+	Score int
+	// Reasons explain the score.
+	Reasons []string
+}
+
+// days returns the text for n days.
+func days(n int) string {
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
+
+// score returns the score of an open item at the provided time and the reasons
+// for it.
+func (tl *List) score(item *Item, t time.Time) (int, []string) {
+	var score int
+	var reasons []string
+
+	if s, ok := priorityScores[item.Priority]; ok {
+		score += s
+		reasons = append(reasons, fmt.Sprintf("priority %s", item.Priority))
+	}
+
+	if !item.Due.IsZero() {
+		n := daysBetween(t, item.Due)
+		switch {
+		case n < 0:
+			overdue := -n
+			if overdue > maxOverdueDays {
+				overdue = maxOverdueDays
+			}
+			score += dueTodayScore + overdueDayScore*overdue
+			reasons = append(reasons, fmt.Sprintf("overdue by %s", days(-n)))
+		case n == 0:
+			score += dueTodayScore
+			reasons = append(reasons, "due today")
+		case n <= dueSoonDays:
+			score += dueTodayScore - dueSoonDayScore*n
+			reasons = append(reasons, fmt.Sprintf("due in %s", days(n)))
+		}
+	}
+
+	if !item.Created.IsZero() {
+		n := daysBetween(item.Created, t)
+		if s := ageWeekScore * (n / 7); s > 0 {
+			if s > maxAgeScore {
+				s = maxAgeScore
+			}
+			score += s
+			reasons = append(reasons, fmt.Sprintf("open for %s", days(n)))
+		}
+	}
+
+	if open := tl.blockers(item); len(open) > 0 {
+		score += blockedScore
+		names := make([]string, 0, len(open))
+		for _, b := range open {
+			names = append(names, displayPath(b))
+		}
+		reasons = append(reasons, fmt.Sprintf("blocked by %s", strings.Join(names, ", ")))
+	}
+	return score, reasons
+}
+
+// hasOpenItems returns whether any of the item's sub-items aren't done.
+func hasOpenItems(item *Item) bool {
+	for _, sub := range item.Items {
+		if !sub.Done {
+			return true
+		}
+	}
+	return false
+}
+
+// Rank returns the open secondaries (items below a primary item) ordered from
+// most to least actionable at the provided time. Items are scored by their
+// priority, due date, age, and whether they are blocked. Items with open
+// sub-items aren't ranked since their sub-items are done first. Ties are
+// broken by path.
+func (tl *List) Rank(t time.Time) []*Candidate {
+	var candidates []*Candidate
+	tl.walkItems(func(path []string, item *Item) {
+		if len(path) < 2 || item.Done || hasOpenItems(item) {
+			return
+		}
+		score, reasons := tl.score(item, t)
+		candidates = append(candidates, &Candidate{
+			Path:    path,
+			Item:    item,
+			Score:   score,
+			Reasons: reasons,
+		})
+	})
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	return candidates
+}
+
+// Next outputs the most actionable items and the reasons they were chosen.
+func (tl *List) Next(output command.Output, data *command.Data) error {
+	n := defaultNextCount
+	if data.Has(countArg) {
+		n = data.Int(countArg)
+	}
+	if n <= 0 {
+		return output.Stderrf("number of items must be positive; got %d\n", n)
+	}
+
+	candidates := tl.Rank(now())
+	if len(candidates) == 0 {
+		output.Stdoutln("Nothing to do")
+		return nil
+	}
+	if len(candidates) > n {
+		candidates = candidates[:n]
+	}
+	for i, c := range candidates {
+		reasons := "no priority or due date"
+		if len(c.Reasons) > 0 {
+			reasons = strings.Join(c.Reasons, ", ")
+		}
+		output.Stdoutf("%d. %s (score %d): %s\n", i+1, displayPath(c.Path), c.Score, reasons)
+	}
+	return nil
+}
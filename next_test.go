@@ -0,0 +1,196 @@
+package todo
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/leep-frog/command"
+)
+
+// rankList returns a list with items of varying actionability at testTime.
+func rankList() *List {
+	date := func(m time.Month, d int) time.Time {
+		return time.Date(2023, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	return &List{
+		Items: map[string]*Item{
+			"home": {
+				Items: map[string]*Item{
+					"chores": {
+						Items: map[string]*Item{
+							"dishes": {},
+						},
+					},
+					"laundry": {Done: true, Priority: "P0"},
+				},
+			},
+			"write": {
+				Items: map[string]*Item{
+					"code": {
+						Priority: "P1",
+						Due:      date(time.February, 3),
+						Created:  testTime,
+					},
+					"deploy": {
+						Priority:  "P0",
+						BlockedBy: [][]string{{"write", "tests"}},
+					},
+					"docs": {
+						Priority: "P3",
+						Created:  date(time.January, 6),
+					},
+					"future": {
+						Due: date(time.February, 8),
+					},
+					"tests": {
+						Due: date(time.February, 1),
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestRank(t *testing.T) {
+	want := []*Candidate{
+		{
+			Path:    []string{"write", "code"},
+			Score:   75,
+			Reasons: []string{"priority P1", "due today"},
+		},
+		{
+			Path:    []string{"write", "tests"},
+			Score:   49,
+			Reasons: []string{"overdue by 2 days"},
+		},
+		{
+			Path:    []string{"write", "future"},
+			Score:   20,
+			Reasons: []string{"due in 5 days"},
+		},
+		{
+			Path:    []string{"write", "docs"},
+			Score:   14,
+			Reasons: []string{"priority P3", "open for 28 days"},
+		},
+		{
+			Path: []string{"home", "chores", "dishes"},
+		},
+		{
+			Path:    []string{"write", "deploy"},
+			Score:   -60,
+			Reasons: []string{"priority P0", "blocked by write > tests"},
+		},
+	}
+	got := rankList().Rank(testTime)
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(Candidate{}, "Item")); diff != "" {
+		t.Errorf("Rank() returned diff (-want, +got):\n%s", diff)
+	}
+}
+
+func TestRankAcrossDaylightSavingTime(t *testing.T) {
+	// time/tzdata is imported so the location loads without a system time
+	// zone database; skip rather than fail if it still can't be loaded.
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data is unavailable: %v", err)
+	}
+	// Clocks moved forward an hour on 2023-03-12, so fewer than 24 hours pass
+	// between the start of that day and the next, and fewer than 7*24 hours pass
+	// between noon on 2023-03-05 and 11:30 on 2023-03-12.
+	l := &List{
+		Items: map[string]*Item{
+			"write": {
+				Items: map[string]*Item{
+					"code": {
+						Due:     time.Date(2023, time.March, 13, 0, 0, 0, 0, loc),
+						Created: time.Date(2023, time.March, 5, 12, 0, 0, 0, loc),
+					},
+				},
+			},
+		},
+	}
+	want := []*Candidate{
+		{
+			Path:    []string{"write", "code"},
+			Score:   41,
+			Reasons: []string{"due in 1 day", "open for 7 days"},
+		},
+	}
+	got := l.Rank(time.Date(2023, time.March, 12, 11, 30, 0, 0, loc))
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(Candidate{}, "Item")); diff != "" {
+		t.Errorf("Rank() returned diff (-want, +got):\n%s", diff)
+	}
+}
+
+func TestNext(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		l          *List
+		args       []string
+		wantData   *command.Data
+		want       []string
+		wantStderr string
+	}{
+		{
+			name: "outputs the top items",
+			l:    rankList(),
+			want: []string{
+				"1. write > code (score 75): priority P1, due today",
+				"2. write > tests (score 49): overdue by 2 days",
+				"3. write > future (score 20): due in 5 days",
+			},
+		},
+		{
+			name: "outputs the provided number of items",
+			l:    rankList(),
+			args: []string{"5"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					countArg: 5,
+				},
+			},
+			want: []string{
+				"1. write > code (score 75): priority P1, due today",
+				"2. write > tests (score 49): overdue by 2 days",
+				"3. write > future (score 20): due in 5 days",
+				"4. write > docs (score 14): priority P3, open for 28 days",
+				"5. home > chores > dishes (score 0): no priority or due date",
+			},
+		},
+		{
+			name: "outputs when there is nothing to do",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {Done: true},
+						},
+					},
+				},
+			},
+			want: []string{
+				"Nothing to do",
+			},
+		},
+		{
+			name: "errors on non-positive number",
+			l:    rankList(),
+			args: []string{"0"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					countArg: 0,
+				},
+			},
+			wantStderr: "number of items must be positive; got 0\n",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			stubNow(t, testTime)
+			executeTest(t, test.l, append([]string{"next"}, test.args...), test.wantData, test.want, test.wantStderr)
+		})
+	}
+}
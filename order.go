@@ -0,0 +1,140 @@
+package todo
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/leep-frog/command"
+)
+
+const (
+	orderUp     = "up"
+	orderDown   = "down"
+	orderTop    = "top"
+	orderBottom = "bottom"
+)
+
+var (
+	orderSuggestions = []string{orderUp, orderDown, orderTop, orderBottom}
+
+	orderDesc = fmt.Sprintf("Path of the item followed by its new position (an index or one of %v)", orderSuggestions)
+)
+
+// orderedKeys returns the keys of items in their manual order. Keys that
+// aren't in order (for example, items added before manual ordering existed)
+// are placed at the end in alphabetical order.
+func orderedKeys(items map[string]*Item, order []string) []string {
+	keys := make([]string, 0, len(items))
+	seen := map[string]bool{}
+	for _, k := range order {
+		if _, ok := items[k]; ok && !seen[k] {
+			keys = append(keys, k)
+			seen[k] = true
+		}
+	}
+
+	var rest []string
+	for k := range items {
+		if !seen[k] {
+			rest = append(rest, k)
+		}
+	}
+	sort.Strings(rest)
+	return append(keys, rest...)
+}
+
+// order returns the manual order of the sub-items of the item at the provided
+// path (or of the primary items if path is empty).
+func (tl *List) order(path []string) *[]string {
+	if len(path) == 0 {
+		return &tl.Order
+	}
+	item, err := tl.get(path)
+	if err != nil {
+		return nil
+	}
+	return &item.Order
+}
+
+// appendOrder adds name to the end of the manual order of the item at path.
+// Sub-items that are missing from the order (e.g. in lists saved before manual
+// ordering existed) are added before it in the order they're displayed in, so
+// the new item isn't listed above them.
+func (tl *List) appendOrder(path []string, name string) {
+	o := tl.order(path)
+	if o == nil {
+		return
+	}
+	var keys []string
+	for _, k := range orderedKeys(tl.children(path), *o) {
+		if k != name {
+			keys = append(keys, k)
+		}
+	}
+	*o = append(keys, name)
+}
+
+// removeOrder removes name from the manual order of the item at path.
+func (tl *List) removeOrder(path []string, name string) {
+	o := tl.order(path)
+	if o == nil {
+		return
+	}
+	var updated []string
+	for _, k := range *o {
+		if k != name {
+			updated = append(updated, k)
+		}
+	}
+	*o = updated
+}
+
+// OrderItem moves an item within the manual order of its siblings. The last
+// element of the provided path is the new position (a 1-based index, "up",
+// "down", "top", or "bottom").
+func (tl *List) OrderItem(output command.Output, data *command.Data) error {
+	path, _, position, err := tl.splitValue(data.StringList(pathArg))
+	if err != nil {
+		return output.Stderrf("%v\n", err)
+	}
+
+	parent, name := path[:len(path)-1], path[len(path)-1]
+	keys := orderedKeys(tl.children(parent), *tl.order(parent))
+	from := 0
+	for i, k := range keys {
+		if k == name {
+			from = i
+		}
+	}
+
+	var to int
+	switch position {
+	case orderUp:
+		to = from - 1
+	case orderDown:
+		to = from + 1
+	case orderTop:
+		to = 0
+	case orderBottom:
+		to = len(keys) - 1
+	default:
+		pos, err := strconv.Atoi(position)
+		if err != nil || pos < 1 {
+			return output.Stderrf("invalid position %q; must be a positive integer or one of %v\n", position, orderSuggestions)
+		}
+		to = pos - 1
+	}
+	if to < 0 {
+		to = 0
+	}
+	if to >= len(keys) {
+		to = len(keys) - 1
+	}
+
+	keys = append(keys[:from], keys[from+1:]...)
+	keys = append(keys[:to], append([]string{name}, keys[to:]...)...)
+	*tl.order(parent) = keys
+	tl.changed = true
+	return nil
+}
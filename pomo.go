@@ -0,0 +1,192 @@
+package todo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/leep-frog/command"
+)
+
+const (
+	workFlag  = "work"
+	breakFlag = "break"
+
+	defaultWork  = 25 * time.Minute
+	defaultBreak = 5 * time.Minute
+
+	// pomoTick is how often the remaining time of a phase is displayed.
+	pomoTick = time.Minute
+)
+
+var (
+	// sleep pauses the current goroutine. Along with now, it is the clock used
+	// by pomodoros and is a variable so tests can stub it.
+	sleep = time.Sleep
+
+	pomoSuggestions  = []string{"15m", "25m", "50m"}
+	breakSuggestions = []string{"0m", "5m", "10m"}
+)
+
+// Pomodoro is a running focus session on an item. It is saved with the list
+// (like Timer) so that the list isn't held open during the countdown, which
+// would overwrite changes made by other runs of the CLI in the meantime. The
+// pomodoro is logged against its item by the first load of the list after
+// its work phase ends.
+type Pomodoro struct {
+	// Path is the path of the item being worked on.
+	Path []string
+	// Start is when the work phase started.
+	Start time.Time
+	// Work is the length of the work phase.
+	Work time.Duration
+	// Break is the length of the break that follows the work phase.
+	Break time.Duration `json:",omitempty"`
+}
+
+// workEnd returns when the pomodoro's work phase ends.
+func (p *Pomodoro) workEnd() time.Time {
+	return p.Start.Add(p.Work)
+}
+
+// pomoSuffix returns the text displayed after an item with pomodoros.
+func pomoSuffix(item *Item) string {
+	switch item.Pomodoros {
+	case 0:
+		return ""
+	case 1:
+		return " (1 pomodoro)"
+	}
+	return fmt.Sprintf(" (%d pomodoros)", item.Pomodoros)
+}
+
+// pomoDuration returns the duration provided by a flag, or def if the flag
+// isn't set.
+func pomoDuration(data *command.Data, flag string, def time.Duration) (time.Duration, error) {
+	if !data.Has(flag) {
+		return def, nil
+	}
+	d, err := time.ParseDuration(data.String(flag))
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid %s duration %q", flag, data.String(flag))
+	}
+	return d, nil
+}
+
+// formatPhase returns the length of a phase rounded up to the minute.
+func formatPhase(d time.Duration) string {
+	return formatDuration(d + time.Minute - time.Nanosecond)
+}
+
+// countdown blocks until the provided time and outputs the remaining time
+// every pomoTick.
+func countdown(output command.Output, end time.Time) {
+	for remaining := end.Sub(now()); remaining > 0; remaining = end.Sub(now()) {
+		output.Stdoutf("%s remaining\n", formatPhase(remaining))
+		if remaining > pomoTick {
+			remaining = pomoTick
+		}
+		sleep(remaining)
+	}
+}
+
+// finishPomodoro logs the running pomodoro against its item (unless the item
+// no longer exists) if its work phase has ended. Returns whether the list
+// changed.
+func (tl *List) finishPomodoro() bool {
+	p := tl.Pomodoro
+	if p == nil || now().Before(p.workEnd()) {
+		return false
+	}
+	tl.Pomodoro = nil
+	if item, err := tl.get(p.Path); err == nil {
+		item.Pomodoros++
+		item.Updated = p.workEnd()
+	}
+	return true
+}
+
+// movePomodoro updates the running pomodoro if its item (or one of the item's
+// parents) moved from src to dst.
+func (tl *List) movePomodoro(src, dst []string) {
+	if tl.Pomodoro != nil && isPrefix(src, tl.Pomodoro.Path) {
+		tl.Pomodoro.Path = append(append([]string{}, dst...), tl.Pomodoro.Path[len(src):]...)
+	}
+}
+
+// StartPomodoro starts a pomodoro on an item. Only one pomodoro can run at a
+// time. The countdown is displayed by WaitPomodoro.
+func (tl *List) StartPomodoro(output command.Output, data *command.Data) error {
+	path := data.StringList(pathArg)
+	if p := tl.Pomodoro; p != nil {
+		return output.Stderrf("a pomodoro is already running for %s (work ends %s)\n", pathString(p.Path), p.workEnd().Format(timeFormat))
+	}
+	item, err := tl.get(path)
+	if err != nil {
+		return output.Stderrf("%v\n", err)
+	}
+	if item.Done {
+		return output.Stderrf("item %s is already done\n", pathString(path))
+	}
+
+	work, err := pomoDuration(data, workFlag, defaultWork)
+	if err != nil {
+		return output.Stderrf("%v\n", err)
+	}
+	if work == 0 {
+		return output.Stderrf("invalid %s duration %q\n", workFlag, data.String(workFlag))
+	}
+	brk, err := pomoDuration(data, breakFlag, defaultBreak)
+	if err != nil {
+		return output.Stderrf("%v\n", err)
+	}
+
+	tl.Pomodoro = &Pomodoro{
+		Path:  append([]string{}, path...),
+		Start: now(),
+		Work:  work,
+		Break: brk,
+	}
+	tl.changed = true
+	output.Stdoutf("Started a %s pomodoro for %s (run `%s pomo wait` for a countdown)\n", formatPhase(work), pathString(path), tl.Name())
+	return nil
+}
+
+// WaitPomodoro counts down the rest of the running pomodoro's work phase
+// followed by its break. It doesn't modify the list, so it can be interrupted
+// at any time and the list can be changed by other runs of the CLI while it
+// blocks.
+func (tl *List) WaitPomodoro(output command.Output, data *command.Data) error {
+	p := tl.Pomodoro
+	if p == nil {
+		return output.Stderr("no pomodoro is running\n")
+	}
+
+	output.Stdoutf("Working on %s for %s\n", pathString(p.Path), formatPhase(p.workEnd().Sub(now())))
+	countdown(output, p.workEnd())
+
+	// The pomodoro is logged by the next load of the list.
+	done := 1
+	if item, err := tl.get(p.Path); err == nil {
+		done += item.Pomodoros
+	}
+	if p.Break == 0 {
+		output.Stdoutf("Pomodoro complete (%d for %s)\n", done, pathString(p.Path))
+		return nil
+	}
+	output.Stdoutf("Pomodoro complete (%d for %s); take a %s break\n", done, pathString(p.Path), formatPhase(p.Break))
+	countdown(output, p.workEnd().Add(p.Break))
+	output.Stdoutln("Break over")
+	return nil
+}
+
+// CancelPomodoro stops the running pomodoro without logging it.
+func (tl *List) CancelPomodoro(output command.Output, data *command.Data) error {
+	p := tl.Pomodoro
+	if p == nil {
+		return output.Stderr("no pomodoro is running\n")
+	}
+	tl.Pomodoro = nil
+	tl.changed = true
+	output.Stdoutf("Cancelled pomodoro for %s\n", pathString(p.Path))
+	return nil
+}
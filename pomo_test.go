@@ -0,0 +1,449 @@
+package todo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/leep-frog/command"
+	"github.com/leep-frog/command/color"
+)
+
+// stubClock makes the package's clock start at tm and advance when sleeping
+// (instead of blocking) for the duration of the test. It returns the total
+// time slept.
+func stubClock(t *testing.T, tm time.Time) *time.Duration {
+	oldNow, oldSleep := now, sleep
+	var slept time.Duration
+	now = func() time.Time { return tm.Add(slept) }
+	sleep = func(d time.Duration) { slept += d }
+	t.Cleanup(func() {
+		now, sleep = oldNow, oldSleep
+	})
+	return &slept
+}
+
+func TestPomodoro(t *testing.T) {
+	// writeCode returns a list with a single sub-item and, if p is set, a
+	// pomodoro running for it.
+	writeCode := func(item *Item, p *Pomodoro) *List {
+		return &List{
+			Items: map[string]*Item{
+				"write": {
+					Items: map[string]*Item{
+						"code": item,
+					},
+				},
+			},
+			Pomodoro: p,
+		}
+	}
+	for _, test := range []struct {
+		name       string
+		l          *List
+		args       []string
+		wantData   *command.Data
+		wantStdout []string
+		wantStderr string
+		wantSlept  time.Duration
+		want       *List
+	}{
+		{
+			name: "starts a pomodoro",
+			l:    writeCode(&Item{Pomodoros: 2}, nil),
+			args: []string{"pomo", "write", "code", "--work", "3m", "--break", "90s"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg:   []string{"write", "code"},
+					workFlag:  "3m",
+					breakFlag: "90s",
+				},
+			},
+			wantStdout: []string{
+				"Started a 3m pomodoro for \"write\", \"code\" (run `td pomo wait` for a countdown)",
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {Pomodoros: 2},
+						},
+					},
+				},
+				Pomodoro: &Pomodoro{
+					Path:  []string{"write", "code"},
+					Start: testTime,
+					Work:  3 * time.Minute,
+					Break: 90 * time.Second,
+				},
+			},
+		},
+		{
+			name: "starts a pomodoro with the default lengths",
+			l:    writeCode(&Item{}, nil),
+			args: []string{"pomo", "write", "code"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"write", "code"},
+				},
+			},
+			wantStdout: []string{
+				"Started a 25m pomodoro for \"write\", \"code\" (run `td pomo wait` for a countdown)",
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {},
+						},
+					},
+				},
+				Pomodoro: &Pomodoro{
+					Path:  []string{"write", "code"},
+					Start: testTime,
+					Work:  defaultWork,
+					Break: defaultBreak,
+				},
+			},
+		},
+		{
+			name: "errors on invalid work duration",
+			l:    writeCode(&Item{}, nil),
+			args: []string{"pomo", "write", "code", "--work", "soon"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg:  []string{"write", "code"},
+					workFlag: "soon",
+				},
+			},
+			wantStderr: "invalid work duration \"soon\"\n",
+		},
+		{
+			name: "errors on empty work phase",
+			l:    writeCode(&Item{}, nil),
+			args: []string{"pomo", "write", "code", "--work", "0s"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg:  []string{"write", "code"},
+					workFlag: "0s",
+				},
+			},
+			wantStderr: "invalid work duration \"0s\"\n",
+		},
+		{
+			name: "errors on negative break",
+			l:    writeCode(&Item{}, nil),
+			args: []string{"pomo", "write", "code", "--break", "-5m"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg:   []string{"write", "code"},
+					breakFlag: "-5m",
+				},
+			},
+			wantStderr: "invalid break duration \"-5m\"\n",
+		},
+		{
+			name: "errors on done item",
+			l:    writeCode(&Item{Done: true}, nil),
+			args: []string{"pomo", "write", "code"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"write", "code"},
+				},
+			},
+			wantStderr: "item \"write\", \"code\" is already done\n",
+		},
+		{
+			name: "errors when a pomodoro is already running",
+			l: writeCode(&Item{}, &Pomodoro{
+				Path:  []string{"write", "code"},
+				Start: testTime.Add(-10 * time.Minute),
+				Work:  defaultWork,
+			}),
+			args: []string{"pomo", "write", "code"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"write", "code"},
+				},
+			},
+			wantStderr: "a pomodoro is already running for \"write\", \"code\" (work ends 2023-02-03 04:20)\n",
+		},
+		{
+			name: "waits for the work phase and the break without changing the list",
+			l: writeCode(&Item{Pomodoros: 2}, &Pomodoro{
+				Path:  []string{"write", "code"},
+				Start: testTime,
+				Work:  3 * time.Minute,
+				Break: 90 * time.Second,
+			}),
+			args: []string{"pomo", "wait"},
+			wantStdout: []string{
+				"Working on \"write\", \"code\" for 3m",
+				"3m remaining",
+				"2m remaining",
+				"1m remaining",
+				"Pomodoro complete (3 for \"write\", \"code\"); take a 2m break",
+				"2m remaining",
+				"1m remaining",
+				"Break over",
+			},
+			wantSlept: 4*time.Minute + 30*time.Second,
+		},
+		{
+			name: "waits for the rest of the default lengths",
+			l: writeCode(&Item{}, &Pomodoro{
+				Path:  []string{"write", "code"},
+				Start: testTime.Add(-10 * time.Minute),
+				Work:  defaultWork,
+				Break: defaultBreak,
+			}),
+			args: []string{"pomo", "wait"},
+			wantStdout: pomoOutput(
+				[]string{"Working on \"write\", \"code\" for 15m"},
+				remaining(15),
+				[]string{"Pomodoro complete (1 for \"write\", \"code\"); take a 5m break"},
+				remaining(5),
+				[]string{"Break over"},
+			),
+			wantSlept: 20 * time.Minute,
+		},
+		{
+			name: "waits for a pomodoro without a break",
+			l: writeCode(&Item{}, &Pomodoro{
+				Path:  []string{"write", "code"},
+				Start: testTime,
+				Work:  time.Minute,
+			}),
+			args: []string{"pomo", "wait"},
+			wantStdout: []string{
+				"Working on \"write\", \"code\" for 1m",
+				"1m remaining",
+				"Pomodoro complete (1 for \"write\", \"code\")",
+			},
+			wantSlept: time.Minute,
+		},
+		{
+			name:       "errors when waiting without a pomodoro",
+			l:          writeCode(&Item{}, nil),
+			args:       []string{"pomo", "wait"},
+			wantStderr: "no pomodoro is running\n",
+		},
+		{
+			name: "cancels a pomodoro",
+			l: writeCode(&Item{}, &Pomodoro{
+				Path:  []string{"write", "code"},
+				Start: testTime,
+				Work:  defaultWork,
+			}),
+			args:       []string{"pomo", "cancel"},
+			wantStdout: []string{"Cancelled pomodoro for \"write\", \"code\""},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:       "errors when cancelling without a pomodoro",
+			l:          writeCode(&Item{}, nil),
+			args:       []string{"pomo", "cancel"},
+			wantStderr: "no pomodoro is running\n",
+		},
+		{
+			name: "moving an item updates its pomodoro",
+			l: writeCode(&Item{}, &Pomodoro{
+				Path:  []string{"write", "code"},
+				Start: testTime,
+				Work:  defaultWork,
+			}),
+			args: []string{"mv", "write", "program"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"write", "program"},
+				},
+			},
+			want: &List{
+				changed: true,
+				Order:   []string{"program"},
+				Items: map[string]*Item{
+					"program": {
+						Updated: testTime,
+						Items: map[string]*Item{
+							"code": {},
+						},
+					},
+				},
+				Pomodoro: &Pomodoro{
+					Path:  []string{"program", "code"},
+					Start: testTime,
+					Work:  defaultWork,
+				},
+			},
+		},
+		{
+			name: "lists pomodoro counts",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code":  {Pomodoros: 1},
+							"tests": {Pomodoros: 4},
+							"docs":  {},
+						},
+					},
+				},
+			},
+			wantStdout: []string{
+				"write",
+				"  [ ] code (1 pomodoro)",
+				"  [ ] docs",
+				"  [ ] tests (4 pomodoros)",
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			slept := stubClock(t, testTime)
+			executeTest(t, test.l, test.args, test.wantData, test.wantStdout, test.wantStderr)
+			command.ChangeTest(t, test.want, test.l, cmp.AllowUnexported(List{}), ignoreHistory, ignoreLog)
+			if *slept != test.wantSlept {
+				t.Errorf("Pomodoro slept for %v; want %v", *slept, test.wantSlept)
+			}
+		})
+	}
+}
+
+// TestPomodoroWithConcurrentChanges runs the CLI the way it is run from a
+// shell, i.e. loading the list before each run and saving it afterwards if it
+// changed, while another shell changes the list during a pomodoro's countdown.
+func TestPomodoroWithConcurrentChanges(t *testing.T) {
+	slept := stubClock(t, testTime)
+	saved := fmt.Sprintf(`{"Version": %d, "Items": {"write": {"Items": {"code": {}}}}}`, currentVersion)
+	// load returns the saved list.
+	load := func() *List {
+		t.Helper()
+		l := &List{}
+		if err := l.Load(saved); err != nil {
+			t.Fatalf("Load() returned error: %v", err)
+		}
+		return l
+	}
+	// save saves l if it changed.
+	save := func(l *List) {
+		t.Helper()
+		if !l.Changed() {
+			return
+		}
+		b, err := json.Marshal(l)
+		if err != nil {
+			t.Fatalf("json.Marshal() returned error: %v", err)
+		}
+		saved = string(b)
+	}
+
+	// Start a pomodoro in one shell.
+	l := load()
+	command.ExecuteTest(t, &command.ExecuteTestCase{
+		Node: l.Node(),
+		Args: []string{"pomo", "write", "code", "-w", "2m", "-b", "0"},
+		WantData: &command.Data{
+			Values: map[string]interface{}{
+				pathArg:   []string{"write", "code"},
+				workFlag:  "2m",
+				breakFlag: "0",
+			},
+		},
+		WantStdout: "Started a 2m pomodoro for \"write\", \"code\" (run `td pomo wait` for a countdown)\n",
+	})
+	save(l)
+
+	// Start the countdown in the same shell, and add an item from another shell
+	// (at the same time since the stubbed clock doesn't advance until the
+	// countdown starts).
+	waiting := load()
+	other := load()
+	command.ExecuteTest(t, &command.ExecuteTestCase{
+		Node: other.Node(),
+		Args: []string{"a", "write", "tests"},
+		WantData: &command.Data{
+			Values: map[string]interface{}{
+				pathArg: []string{"write", "tests"},
+			},
+		},
+	})
+	save(other)
+	command.ExecuteTest(t, &command.ExecuteTestCase{
+		Node: waiting.Node(),
+		Args: []string{"pomo", "wait"},
+		WantStdout: strings.Join([]string{
+			"Working on \"write\", \"code\" for 2m",
+			"2m remaining",
+			"1m remaining",
+			"Pomodoro complete (1 for \"write\", \"code\")",
+			"",
+		}, "\n"),
+	})
+	if waiting.Changed() {
+		t.Errorf("pomo wait changed the list; want it unchanged so it isn't saved")
+	}
+	save(waiting)
+	if *slept != 2*time.Minute {
+		t.Errorf("Pomodoro slept for %v; want %v", *slept, 2*time.Minute)
+	}
+
+	// The next run logs the pomodoro and keeps the other shell's change.
+	got := load()
+	if !got.Changed() {
+		t.Errorf("Load() didn't change the list; want the pomodoro to be logged")
+	}
+	want := &List{
+		Version:        currentVersion,
+		PrimaryFormats: map[string]*color.Format{},
+		Items: map[string]*Item{
+			"write": {
+				Order: []string{"code", "tests"},
+				Items: map[string]*Item{
+					"code": {
+						Pomodoros: 1,
+						Updated:   testTime.Add(2 * time.Minute),
+					},
+					"tests": {
+						Created: testTime,
+						Updated: testTime,
+					},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(List{}), ignoreHistory, ignoreLog); diff != "" {
+		t.Errorf("Load() returned diff (-want, +got):\n%s", diff)
+	}
+}
+
+// pomoOutput returns the concatenation of the provided lines.
+func pomoOutput(lines ...[]string) []string {
+	var all []string
+	for _, l := range lines {
+		all = append(all, l...)
+	}
+	return all
+}
+
+// remaining returns the countdown lines of a phase that lasts n minutes.
+func remaining(n int) []string {
+	var lines []string
+	for i := n; i > 0; i-- {
+		lines = append(lines, fmt.Sprintf("%dm remaining", i))
+	}
+	return lines
+}
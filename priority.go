@@ -0,0 +1,111 @@
+package todo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/leep-frog/command"
+)
+
+const (
+	sortFlag = "sort"
+
+	sortManual   = "manual"
+	sortAlpha    = "alpha"
+	sortPriority = "priority"
+	sortCreated  = "created"
+	sortDue      = "due"
+)
+
+var (
+	// priorities are the valid item priorities, from most to least important.
+	priorities = []string{"P0", "P1", "P2", "P3"}
+
+	sortOptions = []string{sortManual, sortAlpha, sortPriority, sortCreated, sortDue}
+)
+
+// priorityRank returns the sort rank of a priority. Items without a priority
+// are ranked after all other items.
+func priorityRank(p string) int {
+	for i, v := range priorities {
+		if v == p {
+			return i
+		}
+	}
+	return len(priorities)
+}
+
+// sortedItemKeys returns the keys of items ordered by the provided sort option.
+// Ties are broken alphabetically. Items are returned in their manual order if
+// no sort option is provided.
+func sortedItemKeys(items map[string]*Item, order []string, by string) []string {
+	if by == "" || by == sortManual {
+		return orderedKeys(items, order)
+	}
+
+	keys := sortedKeys(items)
+	sort.SliceStable(keys, func(i, j int) bool {
+		a, b := items[keys[i]], items[keys[j]]
+		switch by {
+		case sortPriority:
+			return priorityRank(a.Priority) < priorityRank(b.Priority)
+		case sortCreated:
+			return a.Created.Before(b.Created)
+		case sortDue:
+			// Items without a due date go last.
+			if a.Due.IsZero() || b.Due.IsZero() {
+				return !a.Due.IsZero() && b.Due.IsZero()
+			}
+			return a.Due.Before(b.Due)
+		}
+		return false
+	})
+	return keys
+}
+
+// validateSort returns an error if the provided sort option isn't supported.
+func validateSort(by string) error {
+	for _, o := range sortOptions {
+		if o == by {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid sort option %q; must be one of [%s]", by, strings.Join(sortOptions, ", "))
+}
+
+// parsePriority returns the canonical form of the provided priority.
+func parsePriority(s string) (string, error) {
+	p := strings.ToUpper(s)
+	if !strings.HasPrefix(p, "P") {
+		p = "P" + p
+	}
+	if priorityRank(p) == len(priorities) {
+		return "", fmt.Errorf("invalid priority %q; must be one of [%s]", s, strings.Join(priorities, ", "))
+	}
+	return p, nil
+}
+
+// SetPriority sets the priority of an item. The last element of the provided
+// path is the priority, or clearValue to remove the item's priority.
+func (tl *List) SetPriority(output command.Output, data *command.Data) error {
+	_, item, level, err := tl.splitValue(data.StringList(pathArg))
+	if err != nil {
+		return output.Stderrf("%v\n", err)
+	}
+
+	var priority string
+	if level != clearValue {
+		if priority, err = parsePriority(level); err != nil {
+			return output.Stderrf("%v\n", err)
+		}
+	}
+
+	if item.Priority == priority {
+		return nil
+	}
+	item.Priority = priority
+	item.touch()
+	tl.changed = true
+	return nil
+}
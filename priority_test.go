@@ -0,0 +1,85 @@
+package todo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSortedItemKeys(t *testing.T) {
+	items := map[string]*Item{
+		"a": {
+			Priority: "P2",
+			Created:  testTime.Add(2 * time.Hour),
+		},
+		"b": {
+			Created: testTime,
+			Due:     testTime.AddDate(0, 0, 3),
+		},
+		"c": {
+			Priority: "P0",
+			Created:  testTime.Add(time.Hour),
+			Due:      testTime.AddDate(0, 0, 1),
+		},
+		"d": {
+			Priority: "P2",
+			Created:  testTime.Add(3 * time.Hour),
+		},
+	}
+	for _, test := range []struct {
+		by   string
+		want []string
+	}{
+		{
+			by:   sortManual,
+			want: []string{"d", "b", "a", "c"},
+		},
+		{
+			by:   sortAlpha,
+			want: []string{"a", "b", "c", "d"},
+		},
+		{
+			by:   sortPriority,
+			want: []string{"c", "a", "d", "b"},
+		},
+		{
+			by:   sortCreated,
+			want: []string{"b", "c", "a", "d"},
+		},
+		{
+			by:   sortDue,
+			want: []string{"c", "b", "a", "d"},
+		},
+	} {
+		t.Run(test.by, func(t *testing.T) {
+			if diff := cmp.Diff(test.want, sortedItemKeys(items, []string{"d", "b"}, test.by)); diff != "" {
+				t.Errorf("sortedItemKeys(%q) returned diff (-want, +got):\n%s", test.by, diff)
+			}
+		})
+	}
+}
+
+func TestParsePriority(t *testing.T) {
+	for _, test := range []struct {
+		s       string
+		want    string
+		wantErr bool
+	}{
+		{s: "P1", want: "P1"},
+		{s: "p0", want: "P0"},
+		{s: "3", want: "P3"},
+		{s: "P4", wantErr: true},
+		{s: "high", wantErr: true},
+	} {
+		t.Run(test.s, func(t *testing.T) {
+			got, err := parsePriority(test.s)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("parsePriority(%q) returned error %v; want error %v", test.s, err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("parsePriority(%q) returned %q; want %q", test.s, got, test.want)
+			}
+		})
+	}
+}
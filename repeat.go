@@ -0,0 +1,227 @@
+package todo
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/leep-frog/command"
+)
+
+const (
+	repeatDaily   = "daily"
+	repeatWeekly  = "weekly"
+	repeatMonthly = "monthly"
+	repeatEvery   = "every"
+)
+
+var (
+	intervalRegex = regexp.MustCompile(`^([0-9]+)([dw])$`)
+
+	// repeatSuggestions are the values suggested when completing a recurrence
+	// rule.
+	repeatSuggestions = []string{repeatDaily, repeatWeekly, repeatMonthly, repeatEvery}
+)
+
+// repeatRule describes when a recurring item is next due.
+type repeatRule struct {
+	// kind is one of daily, weekly, monthly, or every.
+	kind string
+	// weekdays are the days that a weekly item is due on. If empty, the item
+	// is due a week after its previous due date.
+	weekdays []time.Weekday
+	// days is the number of days after completion that an "every" item is due.
+	days int
+}
+
+// parseRepeat parses a recurrence rule. Supported rules are "daily", "weekly"
+// (optionally followed by weekdays, e.g. "weekly mon,thu"), "monthly", and
+// "every" followed by an interval (e.g. "every 3d" or "every 2w"), which is
+// relative to when the item is completed.
+func parseRepeat(s string) (*repeatRule, error) {
+	fields := strings.Fields(strings.ToLower(s))
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("invalid repeat rule %q", s)
+	}
+
+	r := &repeatRule{kind: fields[0]}
+	args := fields[1:]
+	switch r.kind {
+	case repeatDaily, repeatMonthly:
+		if len(args) == 0 {
+			return r, nil
+		}
+	case repeatWeekly:
+		seen := map[time.Weekday]bool{}
+		for _, a := range args {
+			for _, d := range strings.Split(a, ",") {
+				if d == "" {
+					continue
+				}
+				wd, ok := weekdays[d]
+				if !ok {
+					return nil, fmt.Errorf("invalid repeat rule %q: unknown weekday %q", s, d)
+				}
+				if !seen[wd] {
+					seen[wd] = true
+					r.weekdays = append(r.weekdays, wd)
+				}
+			}
+		}
+		sort.Slice(r.weekdays, func(i, j int) bool { return r.weekdays[i] < r.weekdays[j] })
+		return r, nil
+	case repeatEvery:
+		if len(args) == 1 {
+			if m := intervalRegex.FindStringSubmatch(args[0]); m != nil {
+				n, err := strconv.Atoi(m[1])
+				if err == nil && n > 0 {
+					if m[2] == "w" {
+						n *= 7
+					}
+					r.days = n
+					return r, nil
+				}
+			}
+		}
+	}
+	return nil, fmt.Errorf("invalid repeat rule %q", s)
+}
+
+// String returns the canonical form of the rule.
+func (r *repeatRule) String() string {
+	switch r.kind {
+	case repeatWeekly:
+		if len(r.weekdays) == 0 {
+			return repeatWeekly
+		}
+		days := make([]string, 0, len(r.weekdays))
+		for _, wd := range r.weekdays {
+			days = append(days, strings.ToLower(wd.String()[:3]))
+		}
+		return fmt.Sprintf("%s %s", repeatWeekly, strings.Join(days, ","))
+	case repeatEvery:
+		if r.days%7 == 0 {
+			return fmt.Sprintf("%s %dw", repeatEvery, r.days/7)
+		}
+		return fmt.Sprintf("%s %dd", repeatEvery, r.days)
+	}
+	return r.kind
+}
+
+// addMonths adds n months to t. Days that don't exist in the resulting month
+// are clamped to its last day (e.g. Jan 31 becomes Feb 28).
+func addMonths(t time.Time, n int) time.Time {
+	y, m, d := t.Date()
+	first := time.Date(y, m+time.Month(n), 1, 0, 0, 0, 0, t.Location())
+	if last := first.AddDate(0, 1, -1).Day(); d > last {
+		d = last
+	}
+	return time.Date(first.Year(), first.Month(), d, 0, 0, 0, 0, t.Location())
+}
+
+// step returns the occurrence of a scheduled rule after t.
+func (r *repeatRule) step(t time.Time) time.Time {
+	switch r.kind {
+	case repeatDaily:
+		return t.AddDate(0, 0, 1)
+	case repeatMonthly:
+		return addMonths(t, 1)
+	}
+	if len(r.weekdays) == 0 {
+		return t.AddDate(0, 0, 7)
+	}
+	for i := 1; ; i++ {
+		next := t.AddDate(0, 0, i)
+		for _, wd := range r.weekdays {
+			if next.Weekday() == wd {
+				return next
+			}
+		}
+	}
+}
+
+// next returns when an item with the provided due date is next due after
+// being completed at the provided time. Scheduled rules advance from the
+// previous due date (or the completion date if there isn't one) and skip any
+// occurrences that have already passed.
+func (r *repeatRule) next(due, completed time.Time) time.Time {
+	today := day(completed)
+	if r.kind == repeatEvery {
+		return today.AddDate(0, 0, r.days)
+	}
+
+	next := today
+	if !due.IsZero() {
+		next = day(due)
+	}
+	for next = r.step(next); !next.After(today); next = r.step(next) {
+	}
+	return next
+}
+
+// repeatSuffix returns the text displayed after a recurring item.
+func repeatSuffix(item *Item) string {
+	if item.Repeat == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (repeats %s)", item.Repeat)
+}
+
+// reopen marks the sub-items of item as not done.
+func reopen(item *Item) {
+	for _, sub := range item.Items {
+		sub.Done = false
+		reopen(sub)
+	}
+}
+
+// completeRecurring records the completion of a recurring item and replaces it
+// with its next instance, i.e. the item and its sub-items are reopened and its
+// due date is moved to the next occurrence. Since the recurring item is never
+// done, completing an occurrence releases the items blocked by it.
+func (tl *List) completeRecurring(output command.Output, path []string, item *Item) error {
+	r, err := parseRepeat(item.Repeat)
+	if err != nil {
+		return output.Stderrf("%v\n", err)
+	}
+
+	t := now()
+	tl.logEvent(eventCompleted, path)
+	item.Due = r.next(item.Due, t)
+	reopen(item)
+	item.touch()
+	tl.changed = true
+	output.Stdoutf("Item %s repeats %s; next due %s\n", pathString(path), item.Repeat, item.Due.Format(dateFormat))
+	tl.releaseBlockers(output, path)
+	return nil
+}
+
+// SetRepeat sets the recurrence rule of an item. The last element of the
+// provided path is the rule (quoted if it has several words, e.g.
+// "weekly mon,thu"), or clearValue to remove the item's rule.
+func (tl *List) SetRepeat(output command.Output, data *command.Data) error {
+	_, item, rule, err := tl.splitValue(data.StringList(pathArg))
+	if err != nil {
+		return output.Stderrf("%v\n", err)
+	}
+
+	var repeat string
+	if rule != clearValue {
+		r, err := parseRepeat(rule)
+		if err != nil {
+			return output.Stderrf("%v\n", err)
+		}
+		repeat = r.String()
+	}
+
+	if item.Repeat == repeat {
+		return nil
+	}
+	item.Repeat = repeat
+	item.touch()
+	tl.changed = true
+	return nil
+}
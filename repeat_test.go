@@ -0,0 +1,321 @@
+package todo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/leep-frog/command"
+)
+
+func TestParseRepeat(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		s       string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "daily",
+			s:    "Daily",
+			want: "daily",
+		},
+		{
+			name: "weekly",
+			s:    "weekly",
+			want: "weekly",
+		},
+		{
+			name: "weekly on weekdays",
+			s:    "weekly thursday,Mon",
+			want: "weekly mon,thu",
+		},
+		{
+			name: "weekly on space separated weekdays",
+			s:    "weekly fri mon fri",
+			want: "weekly mon,fri",
+		},
+		{
+			name: "monthly",
+			s:    "monthly",
+			want: "monthly",
+		},
+		{
+			name: "every days",
+			s:    "every 3d",
+			want: "every 3d",
+		},
+		{
+			name: "every weeks",
+			s:    "every 2w",
+			want: "every 2w",
+		},
+		{
+			name: "every days in weeks",
+			s:    "every 14d",
+			want: "every 2w",
+		},
+		{
+			name:    "unknown rule",
+			s:       "yearly",
+			wantErr: `invalid repeat rule "yearly"`,
+		},
+		{
+			name:    "unknown weekday",
+			s:       "weekly mon,someday",
+			wantErr: `invalid repeat rule "weekly mon,someday": unknown weekday "someday"`,
+		},
+		{
+			name:    "every without interval",
+			s:       "every",
+			wantErr: `invalid repeat rule "every"`,
+		},
+		{
+			name:    "every zero days",
+			s:       "every 0d",
+			wantErr: `invalid repeat rule "every 0d"`,
+		},
+		{
+			name:    "daily with arguments",
+			s:       "daily mon",
+			wantErr: `invalid repeat rule "daily mon"`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseRepeat(test.s)
+			if test.wantErr != "" {
+				if err == nil || err.Error() != test.wantErr {
+					t.Fatalf("parseRepeat(%q) returned error (%v); want (%v)", test.s, err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseRepeat(%q) returned error (%v); want nil", test.s, err)
+			}
+			if got.String() != test.want {
+				t.Errorf("parseRepeat(%q) returned %q; want %q", test.s, got.String(), test.want)
+			}
+		})
+	}
+}
+
+func TestRepeatNext(t *testing.T) {
+	date := func(m time.Month, d int) time.Time {
+		return time.Date(2023, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	// testTime is a Friday.
+	for _, test := range []struct {
+		name string
+		rule string
+		due  time.Time
+		want time.Time
+	}{
+		{
+			name: "daily without due date",
+			rule: "daily",
+			want: date(time.February, 4),
+		},
+		{
+			name: "daily skips missed days",
+			rule: "daily",
+			due:  date(time.January, 30),
+			want: date(time.February, 4),
+		},
+		{
+			name: "daily completed early",
+			rule: "daily",
+			due:  date(time.February, 6),
+			want: date(time.February, 7),
+		},
+		{
+			name: "weekly",
+			rule: "weekly",
+			due:  date(time.February, 3),
+			want: date(time.February, 10),
+		},
+		{
+			name: "weekly on weekdays",
+			rule: "weekly mon,thu",
+			due:  date(time.February, 2),
+			want: date(time.February, 6),
+		},
+		{
+			name: "weekly on weekdays without due date",
+			rule: "weekly fri",
+			want: date(time.February, 10),
+		},
+		{
+			name: "monthly",
+			rule: "monthly",
+			due:  date(time.February, 1),
+			want: date(time.March, 1),
+		},
+		{
+			name: "monthly clamps to the end of the month",
+			rule: "monthly",
+			due:  date(time.January, 31),
+			want: date(time.February, 28),
+		},
+		{
+			name: "every is relative to completion",
+			rule: "every 3d",
+			due:  date(time.January, 1),
+			want: date(time.February, 6),
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			r, err := parseRepeat(test.rule)
+			if err != nil {
+				t.Fatalf("parseRepeat(%q) returned error: %v", test.rule, err)
+			}
+			if got := r.next(test.due, testTime); !got.Equal(test.want) {
+				t.Errorf("next(%v) returned %v; want %v", test.due, got, test.want)
+			}
+		})
+	}
+}
+
+func TestRepeat(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		l          *List
+		args       []string
+		wantData   *command.Data
+		wantStdout []string
+		wantStderr string
+		want       *List
+	}{
+		{
+			name: "sets repeat rule",
+			l: &List{
+				Items: map[string]*Item{
+					"oncall": {
+						Items: map[string]*Item{
+							"rotate keys": {},
+						},
+					},
+				},
+			},
+			args: []string{"repeat", "oncall", "rotate keys", "weekly Mon"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"oncall", "rotate keys", "weekly Mon"},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"oncall": {
+						Items: map[string]*Item{
+							"rotate keys": {
+								Repeat:  "weekly mon",
+								Updated: testTime,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "removes repeat rule",
+			l: &List{
+				Items: map[string]*Item{
+					"oncall": {
+						Repeat: "daily",
+					},
+				},
+			},
+			args: []string{"repeat", "oncall", "none"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"oncall", "none"},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"oncall": {
+						Updated: testTime,
+					},
+				},
+			},
+		},
+		{
+			name: "errors on invalid repeat rule",
+			l: &List{
+				Items: map[string]*Item{
+					"oncall": {},
+				},
+			},
+			args: []string{"repeat", "oncall", "sometimes"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"oncall", "sometimes"},
+				},
+			},
+			wantStderr: "invalid repeat rule \"sometimes\"\n",
+		},
+		{
+			name: "completing a recurring item reopens it with its next due date",
+			l: &List{
+				Items: map[string]*Item{
+					"oncall": {
+						Items: map[string]*Item{
+							"checklist": {
+								Repeat: "weekly mon",
+								Due:    time.Date(2023, time.January, 30, 0, 0, 0, 0, time.UTC),
+								Items: map[string]*Item{
+									"dashboards": {Done: true},
+									"pages": {
+										Done: true,
+										Items: map[string]*Item{
+											"review": {Done: true},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			args: []string{"c", "oncall", "checklist"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"oncall", "checklist"},
+				},
+			},
+			wantStdout: []string{"Item \"oncall\", \"checklist\" repeats weekly mon; next due 2023-02-06"},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"oncall": {
+						Items: map[string]*Item{
+							"checklist": {
+								Repeat:  "weekly mon",
+								Due:     time.Date(2023, time.February, 6, 0, 0, 0, 0, time.UTC),
+								Updated: testTime,
+								Items: map[string]*Item{
+									"dashboards": {},
+									"pages": {
+										Items: map[string]*Item{
+											"review": {},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				Log: []*Event{
+					{Type: eventCompleted, Path: []string{"oncall", "checklist"}, Time: testTime},
+				},
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			stubNow(t, testTime)
+			executeTest(t, test.l, test.args, test.wantData, test.wantStdout, test.wantStderr)
+			command.ChangeTest(t, test.want, test.l, cmp.AllowUnexported(List{}), ignoreHistory)
+		})
+	}
+}
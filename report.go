@@ -0,0 +1,196 @@
+package todo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/leep-frog/command"
+)
+
+const (
+	eventAdded     = "added"
+	eventCompleted = "completed"
+	eventReopened  = "reopened"
+	eventDeleted   = "deleted"
+	eventArchived  = "archived"
+
+	// defaultReportSince is how far back reports go by default.
+	defaultReportSince = "7d"
+
+	formatMarkdown = "md"
+)
+
+var (
+	// logRetention is how long events are kept in the log. Older events are
+	// dropped whenever a new event is logged.
+	logRetention = 90 * 24 * time.Hour
+
+	reportFormats = []string{formatText, formatMarkdown}
+
+	// reportSections are the event types included in reports, in the order
+	// they are displayed.
+	reportSections = []struct {
+		event string
+		title string
+	}{
+		{eventCompleted, "Completed"},
+		{eventAdded, "Added"},
+		{eventDeleted, "Deleted"},
+		{eventArchived, "Archived"},
+	}
+)
+
+// Event records a change to an item.
+type Event struct {
+	// Type is the kind of change (such as "added" or "completed").
+	Type string
+	// Path is the path of the item when it was changed.
+	Path []string
+	// Time is when the change happened.
+	Time time.Time
+}
+
+// logEvent records a change to the item at path and drops events that are
+// older than logRetention.
+func (tl *List) logEvent(eventType string, path []string) {
+	t := now()
+	cutoff := t.Add(-logRetention)
+	kept := tl.Log[:0]
+	for _, e := range tl.Log {
+		if !e.Time.Before(cutoff) {
+			kept = append(kept, e)
+		}
+	}
+	tl.Log = append(kept, &Event{
+		Type: eventType,
+		Path: append([]string{}, path...),
+		Time: t,
+	})
+}
+
+// reportEntries returns the paths of the items changed by each type of event
+// since the provided time, grouped by primary item. Paths are listed in the
+// order they were changed without duplicates. Items that were reopened after
+// being completed aren't included as completed.
+func (tl *List) reportEntries(since time.Time) map[string]map[string][][]string {
+	completed := map[string]bool{}
+	for _, e := range tl.Log {
+		if e.Time.Before(since) {
+			continue
+		}
+		switch e.Type {
+		case eventCompleted:
+			completed[strings.Join(e.Path, "\x00")] = true
+		case eventReopened:
+			delete(completed, strings.Join(e.Path, "\x00"))
+		}
+	}
+
+	entries := map[string]map[string][][]string{}
+	seen := map[string]bool{}
+	for _, e := range tl.Log {
+		if e.Time.Before(since) || e.Type == eventReopened || len(e.Path) == 0 {
+			continue
+		}
+		key := strings.Join(e.Path, "\x00")
+		if e.Type == eventCompleted && !completed[key] {
+			continue
+		}
+		if seen[e.Type+"\x00"+key] {
+			continue
+		}
+		seen[e.Type+"\x00"+key] = true
+
+		p := e.Path[0]
+		if entries[p] == nil {
+			entries[p] = map[string][][]string{}
+		}
+		entries[p][e.Type] = append(entries[p][e.Type], e.Path)
+	}
+	return entries
+}
+
+// reportName returns how an item is displayed in a report. Sub-items are
+// displayed with their path below their primary item.
+func reportName(path []string) string {
+	if len(path) == 1 {
+		return path[0]
+	}
+	return strings.Join(path[1:], " > ")
+}
+
+// Report outputs a summary of the items that were completed, added, deleted,
+// and archived in a time window, grouped by primary item.
+func (tl *List) Report(output command.Output, data *command.Data) error {
+	s := defaultReportSince
+	if data.Has(sinceFlag) {
+		s = data.String(sinceFlag)
+	}
+	since, err := parsePast(s, now())
+	if err != nil {
+		return output.Stderrf("%v\n", err)
+	}
+
+	format := formatText
+	if data.Has(outputFormatFlag) {
+		format = data.String(outputFormatFlag)
+	}
+	markdown := format == formatMarkdown
+	if !markdown && format != formatText {
+		return output.Stderrf("invalid format %q; must be one of [%s]\n", format, strings.Join(reportFormats, ", "))
+	}
+
+	title := fmt.Sprintf("Report since %s", since.Format(dateFormat))
+	if markdown {
+		output.Stdoutln("# " + title)
+	} else {
+		output.Stdoutln(title)
+	}
+
+	entries := tl.reportEntries(since)
+	if len(entries) == 0 {
+		if markdown {
+			output.Stdoutln()
+		}
+		output.Stdoutln("No activity")
+		return nil
+	}
+
+	primaries := make([]string, 0, len(entries))
+	for p := range entries {
+		primaries = append(primaries, p)
+	}
+	sort.Strings(primaries)
+
+	for _, p := range primaries {
+		if markdown {
+			output.Stdoutln()
+			output.Stdoutln("## " + p)
+		} else {
+			output.Stdoutln(tl.PrimaryFormats[p].Format(p))
+		}
+		for _, section := range reportSections {
+			paths := entries[p][section.event]
+			if len(paths) == 0 {
+				continue
+			}
+			if markdown {
+				output.Stdoutln()
+				output.Stdoutln("### " + section.title)
+				output.Stdoutln()
+			} else {
+				output.Stdoutln(fmt.Sprintf("  %s:", section.title))
+			}
+			for _, path := range paths {
+				if markdown {
+					output.Stdoutln("- " + reportName(path))
+				} else {
+					output.Stdoutln("    " + reportName(path))
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,347 @@
+package todo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/leep-frog/command"
+)
+
+func TestLog(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		l          *List
+		args       []string
+		wantData   *command.Data
+		wantStderr string
+		want       []*Event
+	}{
+		{
+			name: "logs each added item",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+			},
+			args: []string{"a", "write", "code", "parser"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"write", "code", "parser"},
+				},
+			},
+			want: []*Event{
+				{Type: eventAdded, Path: []string{"write", "code"}, Time: testTime},
+				{Type: eventAdded, Path: []string{"write", "code", "parser"}, Time: testTime},
+			},
+		},
+		{
+			name: "logs completed item",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {},
+						},
+					},
+				},
+			},
+			args: []string{"c", "write", "code"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"write", "code"},
+				},
+			},
+			want: []*Event{
+				{Type: eventCompleted, Path: []string{"write", "code"}, Time: testTime},
+			},
+		},
+		{
+			name: "logs reopened item",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {Done: true},
+						},
+					},
+				},
+			},
+			args: []string{"u", "write", "code"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"write", "code"},
+				},
+			},
+			want: []*Event{
+				{Type: eventReopened, Path: []string{"write", "code"}, Time: testTime},
+			},
+		},
+		{
+			name: "logs deleted item",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {},
+						},
+					},
+				},
+				Log: []*Event{
+					{Type: eventAdded, Path: []string{"write", "code"}, Time: testTime.AddDate(0, 0, -1)},
+				},
+			},
+			args: []string{"d", "write", "code"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"write", "code"},
+				},
+			},
+			want: []*Event{
+				{Type: eventAdded, Path: []string{"write", "code"}, Time: testTime.AddDate(0, 0, -1)},
+				{Type: eventDeleted, Path: []string{"write", "code"}, Time: testTime},
+			},
+		},
+		{
+			name: "drops events older than the retention window",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+				Log: []*Event{
+					{Type: eventAdded, Path: []string{"sleep"}, Time: testTime.Add(-logRetention - time.Second)},
+					{Type: eventAdded, Path: []string{"write"}, Time: testTime.Add(-logRetention)},
+				},
+			},
+			args: []string{"a", "write", "code"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"write", "code"},
+				},
+			},
+			want: []*Event{
+				{Type: eventAdded, Path: []string{"write"}, Time: testTime.Add(-logRetention)},
+				{Type: eventAdded, Path: []string{"write", "code"}, Time: testTime},
+			},
+		},
+		{
+			name: "logs archived item",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+			},
+			args: []string{"archive", "write"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"write"},
+				},
+			},
+			want: []*Event{
+				{Type: eventArchived, Path: []string{"write"}, Time: testTime},
+			},
+		},
+		{
+			name: "doesn't log failed changes",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+			},
+			args: []string{"c", "write", "code"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"write", "code"},
+				},
+			},
+			wantStderr: "item \"write\", \"code\" does not exist\n",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			stubNow(t, testTime)
+			executeTest(t, test.l, test.args, test.wantData, nil, test.wantStderr)
+			if diff := cmp.Diff(test.want, test.l.Log); diff != "" {
+				t.Errorf("Log diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReport(t *testing.T) {
+	daysAgo := func(n int) time.Time {
+		return testTime.AddDate(0, 0, -n)
+	}
+	l := &List{
+		Items: map[string]*Item{
+			"write": {},
+		},
+		Log: []*Event{
+			{Type: eventAdded, Path: []string{"write", "old"}, Time: daysAgo(10)},
+			{Type: eventAdded, Path: []string{"write"}, Time: daysAgo(5)},
+			{Type: eventAdded, Path: []string{"write", "code"}, Time: daysAgo(5)},
+			{Type: eventAdded, Path: []string{"write", "code", "parser"}, Time: daysAgo(5)},
+			{Type: eventAdded, Path: []string{"sleep"}, Time: daysAgo(4)},
+			{Type: eventCompleted, Path: []string{"write", "code", "parser"}, Time: daysAgo(3)},
+			{Type: eventCompleted, Path: []string{"write", "tests"}, Time: daysAgo(3)},
+			{Type: eventReopened, Path: []string{"write", "tests"}, Time: daysAgo(2)},
+			{Type: eventDeleted, Path: []string{"write", "old"}, Time: daysAgo(2)},
+			{Type: eventCompleted, Path: []string{"write", "code"}, Time: daysAgo(1)},
+			{Type: eventCompleted, Path: []string{"write", "code"}, Time: daysAgo(1)},
+			{Type: eventArchived, Path: []string{"sleep"}, Time: daysAgo(1)},
+		},
+	}
+
+	for _, test := range []struct {
+		name       string
+		args       []string
+		wantData   *command.Data
+		want       []string
+		wantStderr string
+	}{
+		{
+			name: "reports the last week by default",
+			want: []string{
+				"Report since 2023-01-27",
+				"sleep",
+				"  Added:",
+				"    sleep",
+				"  Archived:",
+				"    sleep",
+				"write",
+				"  Completed:",
+				"    code > parser",
+				"    code",
+				"  Added:",
+				"    write",
+				"    code",
+				"    code > parser",
+				"  Deleted:",
+				"    old",
+			},
+		},
+		{
+			name: "reports since a date",
+			args: []string{"--since", "2d"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					sinceFlag: "2d",
+				},
+			},
+			want: []string{
+				"Report since 2023-02-01",
+				"sleep",
+				"  Archived:",
+				"    sleep",
+				"write",
+				"  Completed:",
+				"    code",
+				"  Deleted:",
+				"    old",
+			},
+		},
+		{
+			name: "reports in markdown",
+			args: []string{"-s", "yesterday", "-f", "md"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					sinceFlag:        "yesterday",
+					outputFormatFlag: "md",
+				},
+			},
+			want: []string{
+				"# Report since 2023-02-02",
+				"",
+				"## sleep",
+				"",
+				"### Archived",
+				"",
+				"- sleep",
+				"",
+				"## write",
+				"",
+				"### Completed",
+				"",
+				"- code",
+			},
+		},
+		{
+			name: "reports no activity",
+			args: []string{"--since", "today"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					sinceFlag: "today",
+				},
+			},
+			want: []string{
+				"Report since 2023-02-03",
+				"No activity",
+			},
+		},
+		{
+			name: "errors on invalid date",
+			args: []string{"--since", "later"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					sinceFlag: "later",
+				},
+			},
+			wantStderr: "invalid date \"later\"\n",
+		},
+		{
+			name: "errors on invalid format",
+			args: []string{"--format", "json"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					outputFormatFlag: "json",
+				},
+			},
+			wantStderr: "invalid format \"json\"; must be one of [text, md]\n",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			stubNow(t, testTime)
+			executeTest(t, l, append([]string{"report"}, test.args...), test.wantData, test.want, test.wantStderr)
+		})
+	}
+}
+
+func TestReportExcludesUndoneChanges(t *testing.T) {
+	stubNow(t, testTime)
+	l := &List{
+		Items: map[string]*Item{
+			"write": {
+				Items: map[string]*Item{
+					"code": {},
+				},
+			},
+		},
+	}
+	executeTest(t, l, []string{"c", "write", "code"}, &command.Data{
+		Values: map[string]interface{}{
+			pathArg: []string{"write", "code"},
+		},
+	}, nil, "")
+	executeTest(t, l, []string{"a", "write", "tests"}, &command.Data{
+		Values: map[string]interface{}{
+			pathArg: []string{"write", "tests"},
+		},
+	}, nil, "")
+
+	executeTest(t, l, []string{"undo"}, nil, nil, "")
+	executeTest(t, l, []string{"report"}, nil, []string{
+		"Report since 2023-01-27",
+		"write",
+		"  Completed:",
+		"    code",
+	}, "")
+
+	executeTest(t, l, []string{"redo"}, nil, nil, "")
+	executeTest(t, l, []string{"report"}, nil, []string{
+		"Report since 2023-01-27",
+		"write",
+		"  Completed:",
+		"    code",
+		"  Added:",
+		"    tests",
+	}, "")
+}
@@ -0,0 +1,123 @@
+package todo
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/leep-frog/command"
+	"github.com/leep-frog/command/color"
+)
+
+const (
+	patternArg = "pattern"
+
+	ignoreCaseFlag = "ignore-case"
+	regexFlag      = "regex"
+)
+
+var (
+	matchFormat = &color.Format{Color: color.Green, Thickness: color.Bold}
+)
+
+// searchRegexp returns the regular expression used to match items. Patterns
+// are matched as plain substrings unless the regex flag is set.
+func searchRegexp(data *command.Data) (*regexp.Regexp, error) {
+	pattern := data.String(patternArg)
+	if !data.Bool(regexFlag) {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if data.Bool(ignoreCaseFlag) {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex %q: %v", data.String(patternArg), err)
+	}
+	return re, nil
+}
+
+// highlight returns s with all of the spans matched by re highlighted.
+func highlight(s string, re *regexp.Regexp) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range re.FindAllStringIndex(s, -1) {
+		if m[0] == m[1] {
+			continue
+		}
+		b.WriteString(s[last:m[0]])
+		b.WriteString(matchFormat.Format(s[m[0]:m[1]]))
+		last = m[1]
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// searchMatches returns whether the item's name or note, or any of its
+// sub-items, match re.
+func searchMatches(name string, item *Item, re *regexp.Regexp) bool {
+	if re.MatchString(name) || re.MatchString(item.Note) {
+		return true
+	}
+	for k, sub := range item.Items {
+		if searchMatches(k, sub, re) {
+			return true
+		}
+	}
+	return false
+}
+
+// Search outputs the items whose name or note match a pattern. Matches are
+// displayed in the same shape as ListItems along with the items that contain
+// them.
+func (tl *List) Search(output command.Output, data *command.Data) error {
+	re, err := searchRegexp(data)
+	if err != nil {
+		return output.Stderrf("%v\n", err)
+	}
+
+	for _, p := range orderedKeys(tl.Items, tl.Order) {
+		item := tl.Items[p]
+		if !searchMatches(p, item, re) {
+			continue
+		}
+		name := highlight(p, re)
+		if !re.MatchString(p) {
+			name = tl.PrimaryFormats[p].Format(p)
+		}
+		output.Stdoutln(name + itemSuffix(item))
+		searchNote(output, item, "  ", re)
+		searchItems(output, item, 1, re)
+	}
+	return nil
+}
+
+// searchItems outputs the sub-items of parent that match re.
+func searchItems(output command.Output, parent *Item, depth int, re *regexp.Regexp) {
+	for _, k := range orderedKeys(parent.Items, parent.Order) {
+		item := parent.Items[k]
+		if !searchMatches(k, item, re) {
+			continue
+		}
+		box := openBox
+		if item.Done {
+			box = doneBox
+		}
+		indent := strings.Repeat("  ", depth)
+		output.Stdoutln(fmt.Sprintf("%s%s %s%s", indent, box, highlight(k, re), itemSuffix(item)))
+		searchNote(output, item, indent+strings.Repeat(" ", len(box)+1), re)
+		searchItems(output, item, depth+1, re)
+	}
+}
+
+// searchNote outputs the lines of an item's note that match re.
+func searchNote(output command.Output, item *Item, indent string, re *regexp.Regexp) {
+	if item.Note == "" {
+		return
+	}
+	for _, line := range strings.Split(item.Note, "\n") {
+		if re.MatchString(line) {
+			output.Stdoutln(fmt.Sprintf("%s> %s", indent, highlight(line, re)))
+		}
+	}
+}
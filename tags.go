@@ -0,0 +1,131 @@
+package todo
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/leep-frog/command"
+)
+
+const (
+	tagFlag = "tag"
+)
+
+// hasTag returns whether the item is tagged with tag.
+func (i *Item) hasTag(tag string) bool {
+	for _, t := range i.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// tagged returns whether the item or any of its sub-items is tagged with tag.
+func (i *Item) tagged(tag string) bool {
+	if i.hasTag(tag) {
+		return true
+	}
+	for _, sub := range i.Items {
+		if sub.tagged(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// tags returns all of the tags used in the list in alphabetical order.
+func (tl *List) tags() []string {
+	seen := map[string]bool{}
+	var collect func(map[string]*Item)
+	collect = func(items map[string]*Item) {
+		for _, item := range items {
+			for _, t := range item.Tags {
+				seen[t] = true
+			}
+			collect(item.Items)
+		}
+	}
+	collect(tl.Items)
+
+	tags := make([]string, 0, len(seen))
+	for t := range seen {
+		tags = append(tags, t)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// tagSuffix returns the text displayed after an item with tags.
+func tagSuffix(item *Item) string {
+	if len(item.Tags) == 0 {
+		return ""
+	}
+	return " {" + strings.Join(item.Tags, " ") + "}"
+}
+
+// TagItem adds the tags provided after an item's path to the item (see
+// splitValues).
+func (tl *List) TagItem(output command.Output, data *command.Data) error {
+	_, item, tags, err := tl.splitValues(data.StringList(pathArg))
+	if err != nil {
+		return output.Stderrf("%v\n", err)
+	}
+	if len(tags) == 0 {
+		return output.Stderr("no tags provided\n")
+	}
+
+	added := false
+	for _, t := range tags {
+		if !item.hasTag(t) {
+			item.Tags = append(item.Tags, t)
+			added = true
+		}
+	}
+	if !added {
+		return nil
+	}
+	sort.Strings(item.Tags)
+	item.touch()
+	tl.changed = true
+	return nil
+}
+
+// UntagItem removes the tags provided after an item's path from the item (see
+// splitValues).
+func (tl *List) UntagItem(output command.Output, data *command.Data) error {
+	path, item, tags, err := tl.splitValues(data.StringList(pathArg))
+	if err != nil {
+		return output.Stderrf("%v\n", err)
+	}
+	if len(tags) == 0 {
+		return output.Stderr("no tags provided\n")
+	}
+
+	remove := map[string]bool{}
+	for _, t := range tags {
+		if !item.hasTag(t) {
+			return output.Stderrf("item %s is not tagged with %q\n", pathString(path), t)
+		}
+		remove[t] = true
+	}
+
+	var kept []string
+	for _, t := range item.Tags {
+		if !remove[t] {
+			kept = append(kept, t)
+		}
+	}
+	item.Tags = kept
+	item.touch()
+	tl.changed = true
+	return nil
+}
+
+func tagCompleter(l *List) command.Completer[string] {
+	return command.CompleterFromFunc(func(value string, data *command.Data) (*command.Completion, error) {
+		return &command.Completion{
+			Suggestions: l.tags(),
+		}, nil
+	})
+}
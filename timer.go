@@ -0,0 +1,159 @@
+package todo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/leep-frog/command"
+)
+
+// Session is a period of time spent working on an item.
+type Session struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Timer is a running timer for an item. It is saved with the list so that it
+// keeps running between invocations of the CLI.
+type Timer struct {
+	// Path is the path of the item being timed.
+	Path []string
+	// Start is when the timer was started.
+	Start time.Time
+}
+
+// formatDuration returns d rounded down to the minute (e.g. "1h 5m").
+func formatDuration(d time.Duration) string {
+	m := int(d / time.Minute)
+	if m < 60 {
+		return fmt.Sprintf("%dm", m)
+	}
+	return fmt.Sprintf("%dh %dm", m/60, m%60)
+}
+
+// StartTimer starts a timer for an item. Only one timer can run at a time.
+func (tl *List) StartTimer(output command.Output, data *command.Data) error {
+	path := data.StringList(pathArg)
+	if tl.Timer != nil {
+		return output.Stderrf("a timer is already running for %s (started %s)\n", pathString(tl.Timer.Path), tl.Timer.Start.Format(timeFormat))
+	}
+	item, err := tl.get(path)
+	if err != nil {
+		return output.Stderrf("%v\n", err)
+	}
+	if item.Done {
+		return output.Stderrf("item %s is already done\n", pathString(path))
+	}
+
+	tl.Timer = &Timer{
+		Path:  append([]string{}, path...),
+		Start: now(),
+	}
+	tl.changed = true
+	return nil
+}
+
+// StopTimer stops the running timer and records the session on its item.
+func (tl *List) StopTimer(output command.Output, data *command.Data) error {
+	if tl.Timer == nil {
+		return output.Stderr("no timer is running\n")
+	}
+
+	timer := tl.Timer
+	tl.Timer = nil
+	tl.changed = true
+	item, err := tl.get(timer.Path)
+	if err != nil {
+		output.Stdoutf("Discarded timer for %s since it no longer exists\n", pathString(timer.Path))
+		return nil
+	}
+
+	session := &Session{
+		Start: timer.Start,
+		End:   now(),
+	}
+	item.Sessions = append(item.Sessions, session)
+	output.Stdoutf("Stopped timer for %s after %s\n", pathString(timer.Path), formatDuration(session.End.Sub(session.Start)))
+	return nil
+}
+
+// moveTimer updates the running timer if its item (or one of the item's
+// parents) moved from src to dst.
+func (tl *List) moveTimer(src, dst []string) {
+	if tl.Timer != nil && isPrefix(src, tl.Timer.Path) {
+		tl.Timer.Path = append(append([]string{}, dst...), tl.Timer.Path[len(src):]...)
+	}
+}
+
+// timeSpent returns the time spent on an item since the provided time,
+// including the time of the running timer (if it is for this item).
+func (tl *List) timeSpent(path []string, item *Item, since time.Time) time.Duration {
+	var total time.Duration
+	add := func(start, end time.Time) {
+		if start.Before(since) {
+			start = since
+		}
+		if end.After(start) {
+			total += end.Sub(start)
+		}
+	}
+	for _, s := range item.Sessions {
+		add(s.Start, s.End)
+	}
+	if tl.Timer != nil && samePath(tl.Timer.Path, path) {
+		add(tl.Timer.Start, now())
+	}
+	return total
+}
+
+// ListTime outputs the time spent on each item, and the total for each primary
+// item, optionally limited to the time since a date.
+func (tl *List) ListTime(output command.Output, data *command.Data) error {
+	var since time.Time
+	if data.Has(sinceFlag) {
+		var err error
+		if since, err = parsePast(data.String(sinceFlag), now()); err != nil {
+			return output.Stderrf("%v\n", err)
+		}
+	}
+
+	var total time.Duration
+	for _, p := range orderedKeys(tl.Items, tl.Order) {
+		var lines []string
+		var primaryTotal time.Duration
+		var visit func(path []string, item *Item)
+		visit = func(path []string, item *Item) {
+			if d := tl.timeSpent(path, item, since); d > 0 {
+				primaryTotal += d
+				if len(path) > 1 {
+					line := fmt.Sprintf("  %s %s", strings.Join(path[1:], " > "), formatDuration(d))
+					if tl.Timer != nil && samePath(tl.Timer.Path, path) {
+						line += " (running)"
+					}
+					lines = append(lines, line)
+				}
+			}
+			for _, k := range orderedKeys(item.Items, item.Order) {
+				visit(append(append([]string{}, path...), k), item.Items[k])
+			}
+		}
+		visit([]string{p}, tl.Items[p])
+
+		if primaryTotal == 0 {
+			continue
+		}
+		total += primaryTotal
+		output.Stdoutln(fmt.Sprintf("%s %s", tl.PrimaryFormats[p].Format(p), formatDuration(primaryTotal)))
+		for _, line := range lines {
+			output.Stdoutln(line)
+		}
+	}
+
+	if total == 0 {
+		output.Stdoutln("No time tracked")
+		return nil
+	}
+	output.Stdoutln(fmt.Sprintf("Total %s", formatDuration(total)))
+	return nil
+}
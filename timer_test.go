@@ -0,0 +1,339 @@
+package todo
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/leep-frog/command"
+)
+
+func TestTimer(t *testing.T) {
+	hoursAgo := func(h float64) time.Time {
+		return testTime.Add(-time.Duration(h * float64(time.Hour)))
+	}
+	for _, test := range []struct {
+		name       string
+		l          *List
+		args       []string
+		wantData   *command.Data
+		wantStdout []string
+		wantStderr string
+		want       *List
+	}{
+		{
+			name: "starts timer",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {},
+						},
+					},
+				},
+			},
+			args: []string{"start", "write", "code"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"write", "code"},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {},
+						},
+					},
+				},
+				Timer: &Timer{
+					Path:  []string{"write", "code"},
+					Start: testTime,
+				},
+			},
+		},
+		{
+			name: "errors when a timer is already running",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code":  {},
+							"tests": {},
+						},
+					},
+				},
+				Timer: &Timer{
+					Path:  []string{"write", "code"},
+					Start: hoursAgo(1),
+				},
+			},
+			args: []string{"start", "write", "tests"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"write", "tests"},
+				},
+			},
+			wantStderr: "a timer is already running for \"write\", \"code\" (started 2023-02-03 03:05)\n",
+		},
+		{
+			name: "errors when starting a timer for a missing item",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+			},
+			args: []string{"start", "write", "code"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"write", "code"},
+				},
+			},
+			wantStderr: "item \"write\", \"code\" does not exist\n",
+		},
+		{
+			name: "errors when starting a timer for a done item",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {Done: true},
+						},
+					},
+				},
+			},
+			args: []string{"start", "write", "code"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"write", "code"},
+				},
+			},
+			wantStderr: "item \"write\", \"code\" is already done\n",
+		},
+		{
+			name: "stops timer",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Sessions: []*Session{
+									{Start: hoursAgo(24), End: hoursAgo(23)},
+								},
+							},
+						},
+					},
+				},
+				Timer: &Timer{
+					Path:  []string{"write", "code"},
+					Start: hoursAgo(1.5),
+				},
+			},
+			args:       []string{"stop"},
+			wantStdout: []string{"Stopped timer for \"write\", \"code\" after 1h 30m"},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Sessions: []*Session{
+									{Start: hoursAgo(24), End: hoursAgo(23)},
+									{Start: hoursAgo(1.5), End: testTime},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "discards timer for deleted item",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+				Timer: &Timer{
+					Path:  []string{"write", "code"},
+					Start: hoursAgo(1),
+				},
+			},
+			args:       []string{"stop"},
+			wantStdout: []string{"Discarded timer for \"write\", \"code\" since it no longer exists"},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {},
+				},
+			},
+		},
+		{
+			name:       "errors when no timer is running",
+			args:       []string{"stop"},
+			wantStderr: "no timer is running\n",
+		},
+		{
+			name: "moving an item updates its timer",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {},
+						},
+					},
+				},
+				Timer: &Timer{
+					Path:  []string{"write", "code"},
+					Start: hoursAgo(1),
+				},
+			},
+			args: []string{"mv", "write", "program"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"write", "program"},
+				},
+			},
+			want: &List{
+				changed: true,
+				Order:   []string{"program"},
+				Items: map[string]*Item{
+					"program": {
+						Updated: testTime,
+						Items: map[string]*Item{
+							"code": {},
+						},
+					},
+				},
+				Timer: &Timer{
+					Path:  []string{"program", "code"},
+					Start: hoursAgo(1),
+				},
+			},
+		},
+		{
+			name: "lists time per item and primary",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Sessions: []*Session{
+									{Start: hoursAgo(30), End: hoursAgo(29)},
+									{Start: hoursAgo(3), End: hoursAgo(2.75)},
+								},
+								Items: map[string]*Item{
+									"parser": {
+										Sessions: []*Session{
+											{Start: hoursAgo(2), End: hoursAgo(1.5)},
+										},
+									},
+								},
+							},
+							"tests": {},
+						},
+					},
+					"sleep": {
+						Items: map[string]*Item{
+							"nap": {},
+						},
+					},
+					"read": {
+						Items: map[string]*Item{
+							"book": {},
+						},
+					},
+				},
+				Timer: &Timer{
+					Path:  []string{"read", "book"},
+					Start: hoursAgo(0.5),
+				},
+			},
+			args: []string{"time"},
+			wantStdout: []string{
+				"read 30m",
+				"  book 30m (running)",
+				"write 1h 45m",
+				"  code 1h 15m",
+				"  code > parser 30m",
+				"Total 2h 15m",
+			},
+		},
+		{
+			name: "lists time since a date",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Sessions: []*Session{
+									{Start: hoursAgo(30), End: hoursAgo(29)},
+									{Start: hoursAgo(4.5), End: hoursAgo(3.5)},
+								},
+							},
+						},
+					},
+				},
+			},
+			args: []string{"time", "--since", "today"},
+			wantData: &command.Data{
+				Values: map[string]interface{}{
+					sinceFlag: "today",
+				},
+			},
+			wantStdout: []string{
+				"write 35m",
+				"  code 35m",
+				"Total 35m",
+			},
+		},
+		{
+			name: "lists no time",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+			},
+			args:       []string{"time"},
+			wantStdout: []string{"No time tracked"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			stubNow(t, testTime)
+			if test.l == nil {
+				test.l = &List{}
+			}
+			executeTest(t, test.l, test.args, test.wantData, test.wantStdout, test.wantStderr)
+			command.ChangeTest(t, test.want, test.l, cmp.AllowUnexported(List{}), ignoreHistory)
+		})
+	}
+}
+
+func TestTimerIsSaved(t *testing.T) {
+	l := &List{
+		Items: map[string]*Item{
+			"write": {
+				Items: map[string]*Item{
+					"code": {},
+				},
+			},
+		},
+		Timer: &Timer{
+			Path:  []string{"write", "code"},
+			Start: testTime,
+		},
+	}
+	b, err := json.Marshal(l)
+	if err != nil {
+		t.Fatalf("json.Marshal() returned error: %v", err)
+	}
+
+	loaded := &List{}
+	if err := loaded.Load(string(b)); err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if diff := cmp.Diff(l.Timer, loaded.Timer); diff != "" {
+		t.Errorf("Load() returned timer diff (-want, +got):\n%s", diff)
+	}
+}
@@ -1,65 +1,240 @@
-// Package todo keeps track of a two layer todo list
+// Package todo keeps track of a nested todo list
 package todo
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
-	"sort"
+	"strings"
 
 	"github.com/leep-frog/command"
 	"github.com/leep-frog/command/color"
 )
 
+const (
+	defaultName = "td"
+)
+
+// CLI returns the default todo list.
 func CLI() *List {
-	return &List{}
+	return NamedCLI(defaultName)
+}
+
+// NamedCLI returns a todo list whose CLI has the provided name. Each name is
+// stored separately, so sourcing several named lists keeps their items apart.
+func NamedCLI(name string) *List {
+	return &List{
+		Version: currentVersion,
+		name:    name,
+	}
 }
 
 type List struct {
-	Items map[string]map[string]bool
+	// Version is the version of the list's JSON format. Older versions are
+	// migrated when the list is loaded.
+	Version int
+
+	// Items are the primary items in the list.
+	Items map[string]*Item
+	// Order is the manual display order of the primary items.
+	Order []string `json:",omitempty"`
 
 	PrimaryFormats map[string]*color.Format
 
+	// Archive contains the items that were archived, in the order they were
+	// archived.
+	Archive []*ArchivedItem `json:",omitempty"`
+
+	// Log contains the changes made to items, in the order they were made.
+	Log []*Event `json:",omitempty"`
+	// Timer is the running timer, if any.
+	Timer *Timer `json:",omitempty"`
+	// Pomodoro is the running pomodoro, if any.
+	Pomodoro *Pomodoro `json:",omitempty"`
+
+	// UndoStack and RedoStack contain the list's previous states (see
+	// historyEntry).
+	UndoStack []json.RawMessage `json:",omitempty"`
+	RedoStack []json.RawMessage `json:",omitempty"`
+
 	changed bool
+	// name is the name of the CLI.
+	name string
 }
 
 func (tl *List) Load(jsn string) error {
 	if jsn == "" {
-		tl = &List{}
+		*tl = List{
+			Version: currentVersion,
+			name:    tl.name,
+		}
+		tl.repair()
 		return nil
 	}
 
-	if err := json.Unmarshal([]byte(jsn), tl); err != nil {
+	b, migrated, err := migrate(jsn)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal todo list json: %v", err)
+	}
+
+	// Unknown fields are disallowed so that data isn't silently dropped if a
+	// migration is missing.
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.DisallowUnknownFields()
+	if err := d.Decode(tl); err != nil {
 		return fmt.Errorf("failed to unmarshal todo list json: %v", err)
 	}
+	repaired := tl.repair()
+	finished := tl.finishPomodoro()
+	tl.changed = migrated || repaired || finished
 	return nil
 }
 
+const (
+	openBox = "[ ]"
+	doneBox = "[x]"
+
+	timeFormat = "2006-01-02 15:04"
+)
+
+// listOptions configure how items are displayed.
+type listOptions struct {
+	hideDone bool
+	verbose  bool
+	sort     string
+	// tag, if set, limits the listing to items tagged with it (and the items
+	// that contain them).
+	tag string
+	// list, if set, is used to dim items that are blocked by open items.
+	list *List
+}
+
 func (tl *List) ListItems(output command.Output, data *command.Data) error {
-	ps := make([]string, 0, len(tl.Items))
-	count := 0
-	for k, v := range tl.Items {
-		ps = append(ps, k)
-		count += len(v)
+	opts := &listOptions{
+		hideDone: data.Bool(hideDoneFlag),
+		verbose:  data.Bool(verboseFlag),
+		sort:     sortManual,
+		list:     tl,
+	}
+	if data.Has(sortFlag) {
+		opts.sort = data.String(sortFlag)
+		if err := validateSort(opts.sort); err != nil {
+			return output.Stderrf("%v\n", err)
+		}
+	}
+	if data.Has(tagFlag) {
+		opts.tag = data.String(tagFlag)
+	}
+	if data.Has(outputFormatFlag) {
+		format := data.String(outputFormatFlag)
+		if err := validateOutputFormat(format); err != nil {
+			return output.Stderrf("%v\n", err)
+		}
+		if format != formatText {
+			return tl.writeFormatted(output, format, opts)
+		}
 	}
-	sort.Strings(ps)
 
-	for _, p := range ps {
+	for _, p := range sortedItemKeys(tl.Items, tl.Order, opts.sort) {
+		item := tl.Items[p]
+		subOpts, ok := opts.filter(item)
+		if !ok {
+			continue
+		}
 		f := tl.PrimaryFormats[p]
-		output.Stdoutln(f.Format(p))
-		ss := make([]string, 0, len(tl.Items[p]))
-		for s := range tl.Items[p] {
-			ss = append(ss, s)
+		output.Stdoutln(f.Format(p) + itemSuffix(item))
+		listMetadata(output, item, "  ", opts)
+		listItems(output, item, 1, subOpts)
+	}
+	return nil
+}
+
+// filter returns whether the item should be displayed and the options to use
+// when displaying its sub-items. Items with a matching tag are displayed with
+// all of their sub-items.
+func (opts *listOptions) filter(item *Item) (*listOptions, bool) {
+	if opts.tag == "" {
+		return opts, true
+	}
+	if item.hasTag(opts.tag) {
+		sub := *opts
+		sub.tag = ""
+		return &sub, true
+	}
+	return opts, item.tagged(opts.tag)
+}
+
+// listItems outputs the sub-items of parent.
+func listItems(output command.Output, parent *Item, depth int, opts *listOptions) {
+	for _, k := range sortedItemKeys(parent.Items, parent.Order, opts.sort) {
+		item := parent.Items[k]
+		if item.Done && opts.hideDone {
+			continue
 		}
-		sort.Strings(ss)
-		for _, s := range ss {
-			output.Stdoutln(fmt.Sprintf("  %s", s))
+		subOpts, ok := opts.filter(item)
+		if !ok {
+			continue
+		}
+		box := openBox
+		if item.Done {
+			box = doneBox
+		}
+		indent := strings.Repeat("  ", depth)
+		line := fmt.Sprintf("%s %s%s", box, k, itemSuffix(item))
+		if opts.list != nil && !item.Done {
+			if s := opts.list.blockedSuffix(item); s != "" {
+				line = blockedLine(box, k, item, s)
+			}
+		}
+		output.Stdoutln(indent + line)
+		listMetadata(output, item, indent+strings.Repeat(" ", len(box)+1), opts)
+		listItems(output, item, depth+1, subOpts)
+	}
+}
+
+// itemSuffix returns the text displayed after an item's name.
+func itemSuffix(item *Item) string {
+	return strings.Join(suffixes(item), "")
+}
+
+// suffixes returns the parts of the text displayed after an item's name.
+func suffixes(item *Item) []string {
+	var s string
+	if item.Priority != "" {
+		s = fmt.Sprintf(" [%s]", item.Priority)
+	}
+	return []string{s, dueSuffix(item), repeatSuffix(item), pomoSuffix(item), tagSuffix(item)}
+}
+
+// listMetadata outputs an item's timestamps and note when running verbosely.
+func listMetadata(output command.Output, item *Item, indent string, opts *listOptions) {
+	if !opts.verbose {
+		return
+	}
+
+	var times []string
+	if !item.Created.IsZero() {
+		times = append(times, fmt.Sprintf("created %s", item.Created.Format(timeFormat)))
+	}
+	if !item.Updated.IsZero() {
+		times = append(times, fmt.Sprintf("updated %s", item.Updated.Format(timeFormat)))
+	}
+	if len(times) > 0 {
+		output.Stdoutln(indent + strings.Join(times, " | "))
+	}
+
+	if item.Note != "" {
+		for _, line := range strings.Split(item.Note, "\n") {
+			output.Stdoutln(fmt.Sprintf("%s> %s", indent, line))
 		}
 	}
-	return nil
 }
 
 func (tl *List) FormatPrimary(output command.Output, data *command.Data) error {
 	primary := data.String(primaryArg)
+	if _, err := tl.get([]string{primary}); err != nil {
+		return output.Stderrf("%v\n", err)
+	}
 
 	if tl.PrimaryFormats == nil {
 		tl.PrimaryFormats = map[string]*color.Format{}
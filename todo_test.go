@@ -1,9 +1,12 @@
 package todo
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/leep-frog/command"
 	"github.com/leep-frog/command/color"
@@ -12,15 +15,135 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
+var testTime = time.Date(2023, time.February, 3, 4, 5, 0, 0, time.UTC)
+
+// stubNow makes the package's clock return tm for the duration of the test.
+func stubNow(t *testing.T, tm time.Time) {
+	oldNow := now
+	now = func() time.Time { return tm }
+	t.Cleanup(func() { now = oldNow })
+}
+
+// executeTest runs args against the list's node. Each line of wantStdout is
+// expected to be printed, and executors that print wantStderr are expected to
+// return it (without the trailing newline) as their error.
+func executeTest(t *testing.T, l *List, args []string, wantData *command.Data, wantStdout []string, wantStderr string) {
+	t.Helper()
+	etc := &command.ExecuteTestCase{
+		Node:       l.Node(),
+		Args:       args,
+		WantData:   wantData,
+		WantStderr: wantStderr,
+	}
+	if wantStdout != nil {
+		etc.WantStdout = strings.Join(wantStdout, "\n") + "\n"
+	}
+	if wantStderr != "" {
+		etc.WantErr = errors.New(strings.TrimSuffix(wantStderr, "\n"))
+	}
+	command.ExecuteTest(t, etc)
+}
+
 func TestLoad(t *testing.T) {
 	for _, test := range []struct {
-		name    string
-		json    string
-		want    *List
-		WantErr string
+		name        string
+		l           *List
+		json        string
+		want        *List
+		wantChanged bool
+		WantErr     string
 	}{
 		{
 			name: "handles empty string",
+			want: &List{
+				Version:        currentVersion,
+				Items:          map[string]*Item{},
+				PrimaryFormats: map[string]*color.Format{},
+			},
+		},
+		{
+			name: "resets list on empty string",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+				UndoStack: []json.RawMessage{json.RawMessage(`{}`)},
+			},
+			want: &List{
+				Version:        currentVersion,
+				Items:          map[string]*Item{},
+				PrimaryFormats: map[string]*color.Format{},
+			},
+		},
+		{
+			name: "initializes nil items",
+			json: `{"Items": {"write": {"Items": {"code": null}}, "sleep": null}}`,
+			want: &List{
+				Version: currentVersion,
+				Items: map[string]*Item{
+					"sleep": {},
+					"write": {
+						Items: map[string]*Item{
+							"code": {},
+						},
+					},
+				},
+				PrimaryFormats: map[string]*color.Format{},
+			},
+			wantChanged: true,
+		},
+		{
+			name: "drops orphaned and nil formats",
+			json: fmt.Sprintf(`{"Version": %d, "Items": {"write": {}, "sleep": {}}, "PrimaryFormats": {"write": {"Color": "red"}, "sleep": null, "code": {"Color": "blue"}}}`, currentVersion),
+			want: &List{
+				Version: currentVersion,
+				Items: map[string]*Item{
+					"sleep": {},
+					"write": {},
+				},
+				PrimaryFormats: map[string]*color.Format{
+					"write": {Color: color.Red},
+				},
+			},
+			wantChanged: true,
+		},
+		{
+			name: "removes missing and duplicate items from orders",
+			json: fmt.Sprintf(`{"Version": %d, "Items": {"write": {"Items": {"code": {}}, "Order": ["tests", "code"]}, "sleep": {}}, "Order": ["sleep", "code", "sleep"]}`, currentVersion),
+			want: &List{
+				Version: currentVersion,
+				Order:   []string{"sleep"},
+				Items: map[string]*Item{
+					"sleep": {},
+					"write": {
+						Order: []string{"code"},
+						Items: map[string]*Item{
+							"code": {},
+						},
+					},
+				},
+				PrimaryFormats: map[string]*color.Format{},
+			},
+			wantChanged: true,
+		},
+		{
+			name: "valid list is unchanged",
+			json: fmt.Sprintf(`{"Version": %d, "Items": {"write": {"Items": {"code": {}}, "Order": ["code"]}}, "Order": ["write"], "PrimaryFormats": {"write": {"Color": "red"}}}`, currentVersion),
+			want: &List{
+				Version: currentVersion,
+				Order:   []string{"write"},
+				Items: map[string]*Item{
+					"write": {
+						Order: []string{"code"},
+						Items: map[string]*Item{
+							"code": {},
+						},
+					},
+				},
+				PrimaryFormats: map[string]*color.Format{
+					"write": {Color: color.Red},
+				},
+			},
 		},
 		{
 			name:    "errors on invalid json",
@@ -29,13 +152,65 @@ func TestLoad(t *testing.T) {
 		},
 		{
 			name: "properly unmarshals",
-			json: `{"Items": {"write": {"tests": true, "code": false}}, "PrimaryFormats": {"write": {"Color": "red", "Thickness": true }}}`,
+			json: `{"Items": {"write": {"Items": {"tests": {}, "code": {"Done": true, "Items": {"unit": {}}}}}}, "PrimaryFormats": {"write": {"Color": "red", "Thickness": true }}}`,
+			want: &List{
+				Version: currentVersion,
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"tests": {},
+							"code": {
+								Done: true,
+								Items: map[string]*Item{
+									"unit": {},
+								},
+							},
+						},
+					},
+				},
+				PrimaryFormats: map[string]*color.Format{
+					"write": {
+						Color:     color.Red,
+						Thickness: color.Bold,
+					},
+				},
+			},
+			wantChanged: true,
+		},
+		{
+			name: "unmarshals item metadata",
+			json: `{"Items": {"write": {"Created": "2023-02-03T04:05:00Z", "Items": {"code": {"Created": "2023-02-03T04:05:00Z", "Updated": "2023-02-04T04:05:00Z", "Note": "in go\nwith tests"}}}}}`,
+			want: &List{
+				Version: currentVersion,
+				Items: map[string]*Item{
+					"write": {
+						Created: testTime,
+						Items: map[string]*Item{
+							"code": {
+								Created: testTime,
+								Updated: testTime.Add(24 * time.Hour),
+								Note:    "in go\nwith tests",
+							},
+						},
+					},
+				},
+				PrimaryFormats: map[string]*color.Format{},
+			},
+			wantChanged: true,
+		},
+		{
+			name: "upgrades two layer list",
+			json: `{"Items": {"write": {"tests": true, "code": false}, "sleep": {}}, "PrimaryFormats": {"write": {"Color": "red", "Thickness": true }}}`,
 			want: &List{
-				Items: map[string]map[string]bool{
+				Version: currentVersion,
+				Items: map[string]*Item{
 					"write": {
-						"tests": true,
-						"code":  false,
+						Items: map[string]*Item{
+							"tests": {},
+							"code":  {Done: true},
+						},
 					},
+					"sleep": {},
 				},
 				PrimaryFormats: map[string]*color.Format{
 					"write": {
@@ -44,10 +219,14 @@ func TestLoad(t *testing.T) {
 					},
 				},
 			},
+			wantChanged: true,
 		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
-			l := &List{}
+			l := test.l
+			if l == nil {
+				l = &List{}
+			}
 
 			want := test.want
 			if want == nil {
@@ -66,16 +245,20 @@ func TestLoad(t *testing.T) {
 			if diff := cmp.Diff(want, l, cmpopts.IgnoreUnexported(List{})); diff != "" {
 				t.Errorf("Load(%v) produced todo list diff (-want, +got):\n%s", test.json, diff)
 			}
+			if test.WantErr == "" && l.Changed() != test.wantChanged {
+				t.Errorf("Load(%v) set Changed() to %v; want %v", test.json, l.Changed(), test.wantChanged)
+			}
 		})
 	}
 }
 
 func TestExecution(t *testing.T) {
 	for _, test := range []struct {
-		name string
-		l    *List
-		etc  *command.ExecuteTestCase
-		want *List
+		name  string
+		l     *List
+		stdin string
+		etc   *command.ExecuteTestCase
+		want  *List
 	}{
 		{
 			name: "errors on unknown arg",
@@ -89,10 +272,12 @@ func TestExecution(t *testing.T) {
 		{
 			name: "lists on nil args",
 			l: &List{
-				Items: map[string]map[string]bool{
+				Items: map[string]*Item{
 					"write": {
-						"code":  false,
-						"tests": true,
+						Items: map[string]*Item{
+							"code":  {Done: true},
+							"tests": {},
+						},
 					},
 					"sleep": {},
 				},
@@ -107,8 +292,8 @@ func TestExecution(t *testing.T) {
 				WantStdout: strings.Join([]string{
 					color.Blue.Format(color.Bold.Format("sleep")),
 					"write",
-					"  code",
-					"  tests",
+					"  [x] code",
+					"  [ ] tests",
 					"",
 				}, "\n"),
 			},
@@ -116,10 +301,12 @@ func TestExecution(t *testing.T) {
 		{
 			name: "lists on empty args",
 			l: &List{
-				Items: map[string]map[string]bool{
+				Items: map[string]*Item{
 					"write": {
-						"code":  false,
-						"tests": true,
+						Items: map[string]*Item{
+							"code":  {Done: true},
+							"tests": {},
+						},
 					},
 					"sleep": {},
 				},
@@ -135,328 +322,4126 @@ func TestExecution(t *testing.T) {
 				WantStdout: strings.Join([]string{
 					color.Blue.Format(color.Bold.Format("sleep")),
 					"write",
-					"  code",
-					"  tests",
+					"  [x] code",
+					"  [ ] tests",
 					"",
 				}, "\n"),
 			},
 		},
-		// AddItem
-		{
-			name: "errors if no arguments",
-			etc: &command.ExecuteTestCase{
-				Args:       []string{"a"},
-				WantStderr: "Argument \"primary\" requires at least 1 argument, got 0\n",
-				WantErr:    fmt.Errorf(`Argument "primary" requires at least 1 argument, got 0`),
-			},
-		},
 		{
-			name: "errors if too many arguments",
-			etc: &command.ExecuteTestCase{
-				Args: []string{"a", "write", "tests", "exclusively"},
-				WantData: &command.Data{
-					Values: map[string]interface{}{
-						primaryArg:   "write",
-						secondaryArg: "tests",
+			name: "lists nested items",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Items: map[string]*Item{
+									"parser": {
+										Items: map[string]*Item{
+											"lexer": {Done: true},
+										},
+									},
+									"cli": {},
+								},
+							},
+							"tests": {},
+						},
 					},
 				},
-				WantStderr: "Unprocessed extra args: [exclusively]\n",
-				WantErr:    fmt.Errorf("Unprocessed extra args: [exclusively]"),
+			},
+			etc: &command.ExecuteTestCase{
+				WantStdout: strings.Join([]string{
+					"write",
+					"  [ ] code",
+					"    [ ] cli",
+					"    [ ] parser",
+					"      [x] lexer",
+					"  [ ] tests",
+					"",
+				}, "\n"),
 			},
 		},
 		{
-			name: "adds primary to empty list",
-			etc: &command.ExecuteTestCase{
-				Args: []string{"a", "sleep"},
-				WantData: &command.Data{
-					Values: map[string]interface{}{
-						primaryArg: "sleep",
+			name: "lists without done items",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Done: true,
+								Items: map[string]*Item{
+									"parser": {},
+								},
+							},
+							"tests": {},
+						},
 					},
-				},
-			},
-			want: &List{
-				changed: true,
-				Items: map[string]map[string]bool{
 					"sleep": {},
 				},
 			},
-		},
-		{
-			name: "adds primary and secondary to empty list",
 			etc: &command.ExecuteTestCase{
-				Args: []string{"a", "write", "tests"},
+				Args: []string{"-h"},
 				WantData: &command.Data{
 					Values: map[string]interface{}{
-						primaryArg:   "write",
-						secondaryArg: "tests",
-					},
-				},
-			},
-			want: &List{
-				changed: true,
-				Items: map[string]map[string]bool{
-					"write": {
-						"tests": true,
+						hideDoneFlag: true,
 					},
 				},
+				WantStdout: strings.Join([]string{
+					"sleep",
+					"write",
+					"  [ ] tests",
+					"",
+				}, "\n"),
 			},
 		},
 		{
-			name: "adds just secondary to empty list",
+			name: "lists metadata",
 			l: &List{
-				Items: map[string]map[string]bool{
+				Items: map[string]*Item{
 					"write": {
-						"code": true,
+						Created: testTime,
+						Updated: testTime,
+						Note:    "for work",
+						Items: map[string]*Item{
+							"code": {
+								Done:    true,
+								Created: testTime,
+								Updated: testTime.Add(26 * time.Hour),
+								Note:    "in go\nwith tests",
+							},
+							"tests": {},
+						},
 					},
+					"sleep": {},
 				},
 			},
 			etc: &command.ExecuteTestCase{
-				Args: []string{"a", "write", "tests"},
+				Args: []string{"-v"},
 				WantData: &command.Data{
 					Values: map[string]interface{}{
-						primaryArg:   "write",
-						secondaryArg: "tests",
+						verboseFlag: true,
 					},
 				},
+				WantStdout: strings.Join([]string{
+					"sleep",
+					"write",
+					"  created 2023-02-03 04:05 | updated 2023-02-03 04:05",
+					"  > for work",
+					"  [x] code",
+					"      created 2023-02-03 04:05 | updated 2023-02-04 06:05",
+					"      > in go",
+					"      > with tests",
+					"  [ ] tests",
+					"",
+				}, "\n"),
 			},
-			want: &List{
-				changed: true,
-				Items: map[string]map[string]bool{
+		},
+		{
+			name: "lists due dates",
+			l: &List{
+				Items: map[string]*Item{
 					"write": {
-						"code":  true,
-						"tests": true,
+						Due: testTime.AddDate(0, 0, 7),
+						Items: map[string]*Item{
+							"code": {
+								Due: testTime.AddDate(0, 0, -1),
+							},
+							"docs": {
+								Done: true,
+								Due:  testTime.AddDate(0, 0, -1),
+							},
+							"tests": {
+								Due: day(testTime),
+							},
+							"review": {
+								Due: testTime.AddDate(0, 0, 1),
+							},
+						},
 					},
 				},
 			},
+			etc: &command.ExecuteTestCase{
+				WantStdout: strings.Join([]string{
+					"write (due 2023-02-10)",
+					"  [ ] code " + overdueFormat.Format("(due 2023-02-02)"),
+					"  [x] docs (due 2023-02-02)",
+					"  [ ] review (due 2023-02-04)",
+					"  [ ] tests " + dueTodayFormat.Format("(due 2023-02-03)"),
+					"",
+				}, "\n"),
+			},
 		},
 		{
-			name: "error if primary already exists",
+			name: "lists items by priority",
 			l: &List{
-				Items: map[string]map[string]bool{
-					"write": {},
+				Items: map[string]*Item{
+					"sleep": {
+						Priority: "P1",
+					},
+					"write": {
+						Priority: "P0",
+						Items: map[string]*Item{
+							"code": {
+								Priority: "P3",
+							},
+							"tests": {
+								Priority: "P2",
+							},
+							"docs": {},
+						},
+					},
 				},
 			},
 			etc: &command.ExecuteTestCase{
-				Args: []string{"a", "write"},
+				Args: []string{"--sort", "priority"},
 				WantData: &command.Data{
 					Values: map[string]interface{}{
-						primaryArg: "write",
+						sortFlag: "priority",
 					},
 				},
-				WantStderr: "primary item \"write\" already exists\n",
-				WantErr:    fmt.Errorf(`primary item "write" already exists`),
+				WantStdout: strings.Join([]string{
+					"write [P0]",
+					"  [ ] tests [P2]",
+					"  [ ] code [P3]",
+					"  [ ] docs",
+					"sleep [P1]",
+					"",
+				}, "\n"),
 			},
 		},
 		{
-			name: "error if secondary already exists",
+			name: "lists items by due date",
 			l: &List{
-				Items: map[string]map[string]bool{
+				Items: map[string]*Item{
 					"write": {
-						"code": true,
+						Items: map[string]*Item{
+							"code": {
+								Due: testTime.AddDate(0, 0, 2),
+							},
+							"tests": {
+								Due: testTime.AddDate(0, 0, 1),
+							},
+							"docs": {},
+						},
 					},
 				},
 			},
 			etc: &command.ExecuteTestCase{
-				Args: []string{"a", "write", "code"},
+				Args: []string{"-s", "due"},
 				WantData: &command.Data{
 					Values: map[string]interface{}{
-						primaryArg:   "write",
-						secondaryArg: "code",
+						sortFlag: "due",
 					},
 				},
-				WantStderr: "item \"write\", \"code\" already exists\n",
-				WantErr:    fmt.Errorf(`item "write", "code" already exists`),
-			},
-		},
-		// DeleteItem
-		{
-			name: "errors if no arguments",
-			etc: &command.ExecuteTestCase{
-				Args:       []string{"d"},
-				WantStderr: "Argument \"primary\" requires at least 1 argument, got 0\n",
-				WantErr:    fmt.Errorf(`Argument "primary" requires at least 1 argument, got 0`),
+				WantStdout: strings.Join([]string{
+					"write",
+					"  [ ] tests (due 2023-02-04)",
+					"  [ ] code (due 2023-02-05)",
+					"  [ ] docs",
+					"",
+				}, "\n"),
 			},
 		},
 		{
-			name: "errors if too many arguments",
+			name: "errors on invalid sort",
 			etc: &command.ExecuteTestCase{
-				Args:       []string{"d", "write", "tests", "exclusively"},
-				WantStderr: "Unprocessed extra args: [exclusively]\n",
-				WantErr:    fmt.Errorf("Unprocessed extra args: [exclusively]"),
+				Args: []string{"-s", "size"},
 				WantData: &command.Data{
 					Values: map[string]interface{}{
-						primaryArg:   "write",
-						secondaryArg: "tests",
+						sortFlag: "size",
 					},
 				},
+				WantStderr: "invalid sort option \"size\"; must be one of [manual, alpha, priority, created, due]\n",
+				WantErr:    fmt.Errorf(`invalid sort option "size"; must be one of [manual, alpha, priority, created, due]`),
 			},
 		},
 		{
-			name: "error if empty items and deleting primary",
-			etc: &command.ExecuteTestCase{
-				Args: []string{"d", "write"},
-				WantData: &command.Data{
-					Values: map[string]interface{}{
-						primaryArg: "write",
+			name: "lists items in manual order",
+			l: &List{
+				Order: []string{"write", "sleep"},
+				Items: map[string]*Item{
+					"sleep": {},
+					"write": {
+						Order: []string{"tests", "code"},
+						Items: map[string]*Item{
+							"code":  {},
+							"docs":  {},
+							"tests": {},
+						},
 					},
 				},
-				WantStderr: "can't delete from empty list\n",
-				WantErr:    fmt.Errorf("can't delete from empty list"),
 			},
-		},
-		{
-			name: "error if empty items and deleting secondary",
 			etc: &command.ExecuteTestCase{
-				Args: []string{"d", "write", "code"},
-				WantData: &command.Data{
-					Values: map[string]interface{}{
-						primaryArg:   "write",
-						secondaryArg: "code",
-					},
-				},
-				WantStderr: "can't delete from empty list\n",
-				WantErr:    fmt.Errorf("can't delete from empty list"),
+				WantStdout: strings.Join([]string{
+					"write",
+					"  [ ] tests",
+					"  [ ] code",
+					"  [ ] docs",
+					"sleep",
+					"",
+				}, "\n"),
 			},
 		},
 		{
-			name: "error if unknown primary when deleting primary",
+			name: "lists items alphabetically",
 			l: &List{
-				Items: map[string]map[string]bool{},
+				Order: []string{"write", "sleep"},
+				Items: map[string]*Item{
+					"sleep": {},
+					"write": {
+						Order: []string{"tests", "code"},
+						Items: map[string]*Item{
+							"code":  {},
+							"docs":  {},
+							"tests": {},
+						},
+					},
+				},
 			},
 			etc: &command.ExecuteTestCase{
-				Args:       []string{"d", "write"},
-				WantStderr: "Primary item \"write\" does not exist\n",
-				WantErr:    fmt.Errorf(`Primary item "write" does not exist`),
+				Args: []string{"-s", "alpha"},
 				WantData: &command.Data{
 					Values: map[string]interface{}{
-						primaryArg: "write",
+						sortFlag: "alpha",
 					},
 				},
+				WantStdout: strings.Join([]string{
+					"sleep",
+					"write",
+					"  [ ] code",
+					"  [ ] docs",
+					"  [ ] tests",
+					"",
+				}, "\n"),
 			},
 		},
 		{
-			name: "error if unknown primary when deleting secondary",
+			name: "lists item tags",
 			l: &List{
-				Items: map[string]map[string]bool{},
+				Items: map[string]*Item{
+					"write": {
+						Tags: []string{"@desk"},
+						Items: map[string]*Item{
+							"code": {Tags: []string{"@desk", "@laptop"}},
+						},
+					},
+				},
 			},
 			etc: &command.ExecuteTestCase{
-				Args: []string{"d", "write", "code"},
-				WantStderr: strings.Join([]string{
-					`Primary item "write" does not exist`,
+				WantStdout: strings.Join([]string{
+					"write {@desk}",
+					"  [ ] code {@desk @laptop}",
 					"",
 				}, "\n"),
-				WantErr: fmt.Errorf(`Primary item "write" does not exist`),
-				WantData: &command.Data{
-					Values: map[string]interface{}{
-						primaryArg:   "write",
-						secondaryArg: "code",
-					},
-				},
 			},
 		},
 		{
-			name: "error if unknown secondary",
+			name: "filters items by tag",
 			l: &List{
-				Items: map[string]map[string]bool{
-					"write": {},
+				Items: map[string]*Item{
+					"sleep": {},
+					"home": {
+						Items: map[string]*Item{
+							"dishes": {Tags: []string{"@home"}},
+							"taxes":  {},
+						},
+					},
+					"work": {
+						Items: map[string]*Item{
+							"pager": {
+								Tags: []string{"@oncall"},
+								Items: map[string]*Item{
+									"handoff": {},
+								},
+							},
+							"review": {
+								Items: map[string]*Item{
+									"laundry": {Tags: []string{"@home"}},
+									"slides":  {},
+								},
+							},
+						},
+					},
 				},
 			},
 			etc: &command.ExecuteTestCase{
-				Args:       []string{"d", "write", "code"},
-				WantStderr: "Secondary item \"code\" does not exist\n",
-				WantErr:    fmt.Errorf(`Secondary item "code" does not exist`),
+				Args: []string{"-t", "@home"},
 				WantData: &command.Data{
 					Values: map[string]interface{}{
-						primaryArg:   "write",
-						secondaryArg: "code",
+						tagFlag: "@home",
 					},
 				},
+				WantStdout: strings.Join([]string{
+					"home",
+					"  [ ] dishes {@home}",
+					"work",
+					"  [ ] review",
+					"    [ ] laundry {@home}",
+					"",
+				}, "\n"),
 			},
 		},
 		{
-			name: "error if deleting primary that has secondaries",
+			name: "filter by tag includes sub-items of tagged items",
 			l: &List{
-				Items: map[string]map[string]bool{
-					"write": {
-						"code":  false,
-						"tests": true,
+				Items: map[string]*Item{
+					"sleep": {},
+					"home": {
+						Items: map[string]*Item{
+							"dishes": {Tags: []string{"@home"}},
+							"taxes":  {},
+						},
+					},
+					"work": {
+						Items: map[string]*Item{
+							"pager": {
+								Tags: []string{"@oncall"},
+								Items: map[string]*Item{
+									"handoff": {},
+								},
+							},
+							"review": {
+								Items: map[string]*Item{
+									"laundry": {Tags: []string{"@home"}},
+									"slides":  {},
+								},
+							},
+						},
 					},
 				},
 			},
 			etc: &command.ExecuteTestCase{
-				Args:       []string{"d", "write"},
-				WantStderr: "Can't delete primary item that still has secondary items\n",
-				WantErr:    fmt.Errorf("Can't delete primary item that still has secondary items"),
+				Args: []string{"--tag", "@oncall"},
 				WantData: &command.Data{
 					Values: map[string]interface{}{
-						primaryArg: "write",
+						tagFlag: "@oncall",
 					},
 				},
+				WantStdout: strings.Join([]string{
+					"work",
+					"  [ ] pager {@oncall}",
+					"    [ ] handoff",
+					"",
+				}, "\n"),
 			},
 		},
 		{
-			name: "successfully deletes primary",
+			name: "filter by unused tag lists nothing",
 			l: &List{
-				Items: map[string]map[string]bool{
-					"design": {
-						"solutions": true,
+				Items: map[string]*Item{
+					"sleep": {},
+					"home": {
+						Items: map[string]*Item{
+							"dishes": {Tags: []string{"@home"}},
+							"taxes":  {},
+						},
+					},
+					"work": {
+						Items: map[string]*Item{
+							"pager": {
+								Tags: []string{"@oncall"},
+								Items: map[string]*Item{
+									"handoff": {},
+								},
+							},
+							"review": {
+								Items: map[string]*Item{
+									"laundry": {Tags: []string{"@home"}},
+									"slides":  {},
+								},
+							},
+						},
 					},
-					"write": {},
 				},
 			},
 			etc: &command.ExecuteTestCase{
-				Args: []string{"d", "write"},
+				Args: []string{"-t", "@gym"},
 				WantData: &command.Data{
 					Values: map[string]interface{}{
-						primaryArg: "write",
-					},
-				},
-			},
-			want: &List{
-				changed: true,
-				Items: map[string]map[string]bool{
-					"design": {
-						"solutions": true,
+						tagFlag: "@gym",
 					},
 				},
 			},
 		},
+		// Output formats
 		{
-			name: "successfully deletes secondary",
+			name: "lists as json",
 			l: &List{
-				Items: map[string]map[string]bool{
+				Items: map[string]*Item{
+					"sleep": {},
+					"write": {
+						Created: testTime,
+						Items: map[string]*Item{
+							"code": {
+								Done:     true,
+								Priority: "P1",
+								Tags:     []string{"@desk", "@laptop"},
+							},
+							"tests": {
+								Due:  time.Date(2023, time.February, 10, 0, 0, 0, 0, time.UTC),
+								Note: "cover \"edge\" cases,\nplease",
+							},
+						},
+					},
+				},
+				PrimaryFormats: map[string]*color.Format{
 					"write": {
-						"code":  true,
-						"tests": true,
+						Color:     color.Blue,
+						Thickness: color.Bold,
 					},
 				},
 			},
 			etc: &command.ExecuteTestCase{
-				Args: []string{"d", "write", "code"},
+				Args: []string{"--format", "json"},
 				WantData: &command.Data{
 					Values: map[string]interface{}{
-						primaryArg:   "write",
-						secondaryArg: "code",
+						outputFormatFlag: "json",
 					},
 				},
+				WantStdout: strings.Join([]string{
+					`[`,
+					`  {`,
+					`    "name": "sleep",`,
+					`    "done": false`,
+					`  },`,
+					`  {`,
+					`    "name": "write",`,
+					`    "format": {`,
+					`      "Color": "blue",`,
+					`      "Thickness": true`,
+					`    },`,
+					`    "done": false,`,
+					`    "created": "2023-02-03T04:05:00Z",`,
+					`    "items": [`,
+					`      {`,
+					`        "name": "code",`,
+					`        "done": true,`,
+					`        "priority": "P1",`,
+					`        "tags": [`,
+					`          "@desk",`,
+					`          "@laptop"`,
+					`        ]`,
+					`      },`,
+					`      {`,
+					`        "name": "tests",`,
+					`        "done": false,`,
+					`        "due": "2023-02-10",`,
+					`        "note": "cover \"edge\" cases,\nplease"`,
+					`      }`,
+					`    ]`,
+					`  }`,
+					`]`,
+					"",
+				}, "\n"),
 			},
-			want: &List{
-				changed: true,
-				Items: map[string]map[string]bool{
-					"write": {
-						"tests": true,
-					},
+		},
+		{
+			name: "lists as yaml",
+			l: &List{
+				Items: map[string]*Item{
+					"sleep": {},
+					"write": {
+						Created: testTime,
+						Items: map[string]*Item{
+							"code": {
+								Done:     true,
+								Priority: "P1",
+								Tags:     []string{"@desk", "@laptop"},
+							},
+							"tests": {
+								Due:  time.Date(2023, time.February, 10, 0, 0, 0, 0, time.UTC),
+								Note: "cover \"edge\" cases,\nplease",
+							},
+						},
+					},
+				},
+				PrimaryFormats: map[string]*color.Format{
+					"write": {
+						Color:     color.Blue,
+						Thickness: color.Bold,
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"-f", "yaml"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						outputFormatFlag: "yaml",
+					},
+				},
+				WantStdout: strings.Join([]string{
+					`- name: "sleep"`,
+					`  done: false`,
+					`- name: "write"`,
+					`  format: {"Color":"blue","Thickness":true}`,
+					`  done: false`,
+					`  created: "2023-02-03T04:05:00Z"`,
+					`  items:`,
+					`    - name: "code"`,
+					`      done: true`,
+					`      priority: "P1"`,
+					`      tags:`,
+					`        - "@desk"`,
+					`        - "@laptop"`,
+					`    - name: "tests"`,
+					`      done: false`,
+					`      due: "2023-02-10"`,
+					`      note: "cover \"edge\" cases,\nplease"`,
+					"",
+				}, "\n"),
+			},
+		},
+		{
+			name: "lists as csv",
+			l: &List{
+				Items: map[string]*Item{
+					"sleep": {},
+					"write": {
+						Created: testTime,
+						Items: map[string]*Item{
+							"code": {
+								Done:     true,
+								Priority: "P1",
+								Tags:     []string{"@desk", "@laptop"},
+							},
+							"tests": {
+								Due:  time.Date(2023, time.February, 10, 0, 0, 0, 0, time.UTC),
+								Note: "cover \"edge\" cases,\nplease",
+							},
+						},
+					},
+				},
+				PrimaryFormats: map[string]*color.Format{
+					"write": {
+						Color:     color.Blue,
+						Thickness: color.Bold,
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"-f", "csv"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						outputFormatFlag: "csv",
+					},
+				},
+				WantStdout: strings.Join([]string{
+					`path,done,priority,due,tags,note,created,updated`,
+					`sleep,false,,,,,,`,
+					`write,false,,,,,2023-02-03T04:05:00Z,`,
+					`write/code,true,P1,,@desk @laptop,,,`,
+					`write/tests,false,,2023-02-10,,"cover ""edge"" cases,`,
+					`please",,`,
+					"",
+				}, "\n"),
+			},
+		},
+		{
+			name: "lists as tsv",
+			l: &List{
+				Items: map[string]*Item{
+					"sleep": {},
+					"write": {
+						Created: testTime,
+						Items: map[string]*Item{
+							"code": {
+								Done:     true,
+								Priority: "P1",
+								Tags:     []string{"@desk", "@laptop"},
+							},
+							"tests": {
+								Due:  time.Date(2023, time.February, 10, 0, 0, 0, 0, time.UTC),
+								Note: "cover \"edge\" cases,\nplease",
+							},
+						},
+					},
+				},
+				PrimaryFormats: map[string]*color.Format{
+					"write": {
+						Color:     color.Blue,
+						Thickness: color.Bold,
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"-f", "tsv"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						outputFormatFlag: "tsv",
+					},
+				},
+				WantStdout: strings.Join([]string{
+					"path\tdone\tpriority\tdue\ttags\tnote\tcreated\tupdated",
+					"sleep\tfalse\t\t\t\t\t\t",
+					"write\tfalse\t\t\t\t\t2023-02-03T04:05:00Z\t",
+					"write/code\ttrue\tP1\t\t@desk @laptop\t\t\t",
+					"write/tests\tfalse\t\t2023-02-10\t\t\"cover \"\"edge\"\" cases,",
+					"please\"\t\t",
+					"",
+				}, "\n"),
+			},
+		},
+		{
+			name: "formatted lists apply listing flags",
+			l: &List{
+				Items: map[string]*Item{
+					"sleep": {},
+					"write": {
+						Created: testTime,
+						Items: map[string]*Item{
+							"code": {
+								Done:     true,
+								Priority: "P1",
+								Tags:     []string{"@desk", "@laptop"},
+							},
+							"tests": {
+								Due:  time.Date(2023, time.February, 10, 0, 0, 0, 0, time.UTC),
+								Note: "cover \"edge\" cases,\nplease",
+							},
+						},
+					},
+				},
+				PrimaryFormats: map[string]*color.Format{
+					"write": {
+						Color:     color.Blue,
+						Thickness: color.Bold,
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"-f", "csv", "-h", "-t", "@laptop"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						outputFormatFlag: "csv",
+						hideDoneFlag:     true,
+						tagFlag:          "@laptop",
+					},
+				},
+				WantStdout: strings.Join([]string{
+					"path,done,priority,due,tags,note,created,updated",
+					"write,false,,,,,2023-02-03T04:05:00Z,",
+					"",
+				}, "\n"),
+			},
+		},
+		{
+			name: "lists empty list as json",
+			etc: &command.ExecuteTestCase{
+				Args: []string{"-f", "json"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						outputFormatFlag: "json",
+					},
+				},
+				WantStdout: strings.Join([]string{
+					"[]",
+					"",
+				}, "\n"),
+			},
+		},
+		{
+			name: "lists empty list as yaml",
+			etc: &command.ExecuteTestCase{
+				Args: []string{"-f", "yaml"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						outputFormatFlag: "yaml",
+					},
+				},
+				WantStdout: strings.Join([]string{
+					"[]",
+					"",
+				}, "\n"),
+			},
+		},
+		{
+			name: "lists as text",
+			l: &List{
+				Items: map[string]*Item{
+					"sleep": {},
+					"write": {
+						Created: testTime,
+						Items: map[string]*Item{
+							"code": {
+								Done:     true,
+								Priority: "P1",
+								Tags:     []string{"@desk", "@laptop"},
+							},
+							"tests": {
+								Due:  time.Date(2023, time.February, 10, 0, 0, 0, 0, time.UTC),
+								Note: "cover \"edge\" cases,\nplease",
+							},
+						},
+					},
+				},
+				PrimaryFormats: map[string]*color.Format{
+					"write": {
+						Color:     color.Blue,
+						Thickness: color.Bold,
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"-f", "text"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						outputFormatFlag: "text",
+					},
+				},
+				WantStdout: strings.Join([]string{
+					"sleep",
+					color.Blue.Format(color.Bold.Format("write")),
+					"  [x] code [P1] {@desk @laptop}",
+					"  [ ] tests (due 2023-02-10)",
+					"",
+				}, "\n"),
+			},
+		},
+		{
+			name: "errors on unknown format",
+			l: &List{
+				Items: map[string]*Item{
+					"sleep": {},
+					"write": {
+						Created: testTime,
+						Items: map[string]*Item{
+							"code": {
+								Done:     true,
+								Priority: "P1",
+								Tags:     []string{"@desk", "@laptop"},
+							},
+							"tests": {
+								Due:  time.Date(2023, time.February, 10, 0, 0, 0, 0, time.UTC),
+								Note: "cover \"edge\" cases,\nplease",
+							},
+						},
+					},
+				},
+				PrimaryFormats: map[string]*color.Format{
+					"write": {
+						Color:     color.Blue,
+						Thickness: color.Bold,
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"-f", "xml"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						outputFormatFlag: "xml",
+					},
+				},
+				WantStderr: "invalid format \"xml\"; must be one of [text, json, yaml, csv, tsv]\n",
+				WantErr:    fmt.Errorf("invalid format \"xml\"; must be one of [text, json, yaml, csv, tsv]"),
+			},
+		},
+		// AddItem
+		{
+			name: "errors if no arguments",
+			etc: &command.ExecuteTestCase{
+				Args:       []string{"a"},
+				WantStderr: "Argument \"path\" requires at least 1 argument, got 0\n",
+				WantErr:    fmt.Errorf(`Argument "path" requires at least 1 argument, got 0`),
+			},
+		},
+		{
+			name: "adds primary to empty list",
+			etc: &command.ExecuteTestCase{
+				Args: []string{"a", "sleep"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"sleep"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Order:   []string{"sleep"},
+				Items: map[string]*Item{
+					"sleep": {
+						Created: testTime,
+						Updated: testTime,
+					},
+				},
+			},
+		},
+		{
+			name: "adds primary and secondary to empty list",
+			etc: &command.ExecuteTestCase{
+				Args: []string{"a", "write", "tests"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "tests"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Order:   []string{"write"},
+				Items: map[string]*Item{
+					"write": {
+						Created: testTime,
+						Updated: testTime,
+						Order:   []string{"tests"},
+						Items: map[string]*Item{
+							"tests": {
+								Created: testTime,
+								Updated: testTime,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "adds just secondary to existing primary",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"a", "write", "tests"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "tests"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Order: []string{"code", "tests"},
+						Items: map[string]*Item{
+							"code": {},
+							"tests": {
+								Created: testTime,
+								Updated: testTime,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "adds item after existing sub-items when order is nil",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"tests": {},
+							"code":  {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"a", "write", "docs"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "docs"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Order: []string{"code", "tests", "docs"},
+						Items: map[string]*Item{
+							"code": {},
+							"docs": {
+								Created: testTime,
+								Updated: testTime,
+							},
+							"tests": {},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "adds item after ordered and unordered sub-items",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Order: []string{"tests"},
+						Items: map[string]*Item{
+							"tests": {},
+							"code":  {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"a", "write", "docs"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "docs"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Order: []string{"tests", "code", "docs"},
+						Items: map[string]*Item{
+							"code": {},
+							"docs": {
+								Created: testTime,
+								Updated: testTime,
+							},
+							"tests": {},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "adds deeply nested items",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"a", "write", "code", "parser", "lexer"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code", "parser", "lexer"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Order: []string{"parser"},
+								Items: map[string]*Item{
+									"parser": {
+										Created: testTime,
+										Updated: testTime,
+										Order:   []string{"lexer"},
+										Items: map[string]*Item{
+											"lexer": {
+												Created: testTime,
+												Updated: testTime,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "adds item with due date",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"a", "write", "code", "--due", "tomorrow"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code"},
+						dueFlag: "tomorrow",
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Order: []string{"code"},
+						Items: map[string]*Item{
+							"code": {
+								Created: testTime,
+								Updated: testTime,
+								Due:     time.Date(2023, time.February, 4, 0, 0, 0, 0, time.UTC),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "add errors on invalid due date",
+			etc: &command.ExecuteTestCase{
+				Args: []string{"a", "write", "-d", "someday"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write"},
+						dueFlag: "someday",
+					},
+				},
+				WantStderr: "invalid due date \"someday\"\n",
+				WantErr:    fmt.Errorf(`invalid due date "someday"`),
+			},
+		},
+		{
+			name: "error if primary already exists",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"a", "write"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write"},
+					},
+				},
+				WantStderr: "item \"write\" already exists\n",
+				WantErr:    fmt.Errorf(`item "write" already exists`),
+			},
+		},
+		{
+			name: "error if secondary already exists",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"a", "write", "code"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code"},
+					},
+				},
+				WantStderr: "item \"write\", \"code\" already exists\n",
+				WantErr:    fmt.Errorf(`item "write", "code" already exists`),
+			},
+		},
+		// DeleteItem
+		{
+			name: "errors if no arguments",
+			etc: &command.ExecuteTestCase{
+				Args:       []string{"d"},
+				WantStderr: "Argument \"path\" requires at least 1 argument, got 0\n",
+				WantErr:    fmt.Errorf(`Argument "path" requires at least 1 argument, got 0`),
+			},
+		},
+		{
+			name: "error if empty items and deleting primary",
+			etc: &command.ExecuteTestCase{
+				Args: []string{"d", "write"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write"},
+					},
+				},
+				WantStderr: "can't delete from empty list\n",
+				WantErr:    fmt.Errorf("can't delete from empty list"),
+			},
+		},
+		{
+			name: "error if empty items and deleting secondary",
+			etc: &command.ExecuteTestCase{
+				Args: []string{"d", "write", "code"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code"},
+					},
+				},
+				WantStderr: "can't delete from empty list\n",
+				WantErr:    fmt.Errorf("can't delete from empty list"),
+			},
+		},
+		{
+			name: "error if unknown primary when deleting primary",
+			l: &List{
+				Items: map[string]*Item{},
+			},
+			etc: &command.ExecuteTestCase{
+				Args:       []string{"d", "write"},
+				WantStderr: "item \"write\" does not exist\n",
+				WantErr:    fmt.Errorf(`item "write" does not exist`),
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write"},
+					},
+				},
+			},
+		},
+		{
+			name: "error if unknown primary when deleting secondary",
+			l: &List{
+				Items: map[string]*Item{},
+			},
+			etc: &command.ExecuteTestCase{
+				Args:       []string{"d", "write", "code"},
+				WantStderr: "item \"write\" does not exist\n",
+				WantErr:    fmt.Errorf(`item "write" does not exist`),
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code"},
+					},
+				},
+			},
+		},
+		{
+			name: "error if unknown secondary",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args:       []string{"d", "write", "code"},
+				WantStderr: "item \"write\", \"code\" does not exist\n",
+				WantErr:    fmt.Errorf(`item "write", "code" does not exist`),
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code"},
+					},
+				},
+			},
+		},
+		{
+			name: "error if deleting primary that has secondaries",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code":  {Done: true},
+							"tests": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args:       []string{"d", "write"},
+				WantStderr: "Can't delete item that still has sub-items\n",
+				WantErr:    fmt.Errorf("Can't delete item that still has sub-items"),
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write"},
+					},
+				},
+			},
+		},
+		{
+			name: "successfully deletes primary",
+			l: &List{
+				Items: map[string]*Item{
+					"design": {
+						Items: map[string]*Item{
+							"solutions": {},
+						},
+					},
+					"write": {},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"d", "write"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"design": {
+						Items: map[string]*Item{
+							"solutions": {},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "successfully deletes secondary",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code":  {},
+							"tests": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"d", "write", "code"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"tests": {},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "successfully deletes nested item",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Items: map[string]*Item{
+									"parser": {},
+									"cli":    {},
+								},
+							},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"d", "write", "code", "cli"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code", "cli"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Items: map[string]*Item{
+									"parser": {},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "successfully deletes primary and its format",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+				PrimaryFormats: map[string]*color.Format{
+					"write": {
+						Color: color.Red,
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"d", "write"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write"},
+					},
+				},
+			},
+			want: &List{
+				changed:        true,
+				Items:          map[string]*Item{},
+				PrimaryFormats: map[string]*color.Format{},
+			},
+		},
+		{
+			name: "recursively deletes primary",
+			l: &List{
+				Items: map[string]*Item{
+					"design": {},
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Items: map[string]*Item{
+									"parser": {Done: true},
+								},
+							},
+							"tests": {},
+						},
+					},
+				},
+				PrimaryFormats: map[string]*color.Format{
+					"write": {
+						Color: color.Red,
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"d", "write", "-r"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg:       []string{"write"},
+						recursiveFlag: true,
+					},
+				},
+				WantStdout: strings.Join([]string{
+					`"write"`,
+					"  [ ] code",
+					"    [x] parser",
+					"  [ ] tests",
+					"",
+				}, "\n"),
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"design": {},
+				},
+				PrimaryFormats: map[string]*color.Format{},
+			},
+		},
+		{
+			name: "recursively deletes secondary",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Items: map[string]*Item{
+									"parser": {},
+								},
+							},
+							"tests": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"d", "--recursive", "write", "code"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg:       []string{"write", "code"},
+						recursiveFlag: true,
+					},
+				},
+				WantStdout: strings.Join([]string{
+					`"write", "code"`,
+					"  [ ] parser",
+					"",
+				}, "\n"),
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"tests": {},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "recursive delete prompts when over the default threshold",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"a": {},
+							"b": {},
+							"c": {},
+							"d": {},
+							"e": {},
+						},
+					},
+				},
+			},
+			stdin: "y\n",
+			etc: &command.ExecuteTestCase{
+				Args: []string{"d", "-r", "write"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg:       []string{"write"},
+						recursiveFlag: true,
+					},
+				},
+				WantStdout: strings.Join([]string{
+					`"write"`,
+					"  [ ] a",
+					"  [ ] b",
+					"  [ ] c",
+					"  [ ] d",
+					"  [ ] e",
+					"Delete 6 items? [y/N] ",
+				}, "\n"),
+			},
+			want: &List{
+				changed: true,
+				Items:   map[string]*Item{},
+			},
+		},
+		{
+			name: "recursive delete prompts when over the provided threshold",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code":  {},
+							"tests": {},
+						},
+					},
+				},
+			},
+			stdin: "YES\n",
+			etc: &command.ExecuteTestCase{
+				Args: []string{"d", "-r", "write", "-c", "2"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg:         []string{"write"},
+						recursiveFlag:   true,
+						confirmOverFlag: 2,
+					},
+				},
+				WantStdout: strings.Join([]string{
+					`"write"`,
+					"  [ ] code",
+					"  [ ] tests",
+					"Delete 3 items? [y/N] ",
+				}, "\n"),
+			},
+			want: &List{
+				changed: true,
+				Items:   map[string]*Item{},
+			},
+		},
+		{
+			name: "recursive delete is aborted if not confirmed",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code":  {},
+							"tests": {},
+						},
+					},
+				},
+			},
+			stdin: "n\n",
+			etc: &command.ExecuteTestCase{
+				Args: []string{"d", "-r", "write", "-c", "2"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg:         []string{"write"},
+						recursiveFlag:   true,
+						confirmOverFlag: 2,
+					},
+				},
+				WantStdout: strings.Join([]string{
+					`"write"`,
+					"  [ ] code",
+					"  [ ] tests",
+					"Delete 3 items? [y/N] ",
+				}, "\n"),
+				WantStderr: "delete aborted\n",
+				WantErr:    fmt.Errorf("delete aborted"),
+			},
+		},
+		{
+			name: "recursive delete is aborted if no response",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code":  {},
+							"tests": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"d", "-r", "write", "-c", "2"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg:         []string{"write"},
+						recursiveFlag:   true,
+						confirmOverFlag: 2,
+					},
+				},
+				WantStdout: strings.Join([]string{
+					`"write"`,
+					"  [ ] code",
+					"  [ ] tests",
+					"Delete 3 items? [y/N] ",
+				}, "\n"),
+				WantStderr: "delete aborted\n",
+				WantErr:    fmt.Errorf("delete aborted"),
+			},
+		},
+		{
+			name: "forced recursive delete does not prompt",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code":  {},
+							"tests": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"d", "-r", "-f", "write", "-c", "2"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg:         []string{"write"},
+						recursiveFlag:   true,
+						forceFlag:       true,
+						confirmOverFlag: 2,
+					},
+				},
+				WantStdout: strings.Join([]string{
+					`"write"`,
+					"  [ ] code",
+					"  [ ] tests",
+					"",
+				}, "\n"),
+			},
+			want: &List{
+				changed: true,
+				Items:   map[string]*Item{},
+			},
+		},
+		// MoveItem
+		{
+			name: "move requires destination",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"mv", "write"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write"},
+					},
+				},
+				WantStderr: "Argument \"path\" requires at least 2 arguments, got 1\n",
+				WantErr:    fmt.Errorf(`Argument "path" requires at least 2 arguments, got 1`),
+			},
+		},
+		{
+			name: "move errors on unknown source",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"mv", "wirte", "write"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"wirte", "write"},
+					},
+				},
+				WantStderr: "item \"wirte\" does not exist\n",
+				WantErr:    fmt.Errorf(`item "wirte" does not exist`),
+			},
+		},
+		{
+			name: "move errors if no destination",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"mv", "write", "code"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code"},
+					},
+				},
+				WantStderr: "no destination provided (separate the item and its destination with \"--\")\n",
+				WantErr:    fmt.Errorf(`no destination provided (separate the item and its destination with "--")`),
+			},
+		},
+		{
+			name: "move errors if destination names a sub-item without separator",
+			l: &List{
+				Items: map[string]*Item{
+					"work": {
+						Items: map[string]*Item{
+							"home": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"mv", "work", "home"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"work", "home"},
+					},
+				},
+				WantStderr: "no destination provided (separate the item and its destination with \"--\")\n",
+				WantErr:    fmt.Errorf(`no destination provided (separate the item and its destination with "--")`),
+			},
+		},
+		{
+			name: "move renames item to the name of its sub-item with separator",
+			l: &List{
+				Items: map[string]*Item{
+					"work": {
+						Items: map[string]*Item{
+							"home": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"mv", "work", "--", "home"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"work", "--", "home"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Order:   []string{"home"},
+				Items: map[string]*Item{
+					"home": {
+						Updated: testTime,
+						Items: map[string]*Item{
+							"home": {},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "move with separator errors on unknown item",
+			l: &List{
+				Items: map[string]*Item{
+					"work": {},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"mv", "work", "code", "--", "home"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"work", "code", "--", "home"},
+					},
+				},
+				WantStderr: "item \"work\", \"code\" does not exist\n",
+				WantErr:    fmt.Errorf(`item "work", "code" does not exist`),
+			},
+		},
+		{
+			name: "move errors if destination parent does not exist",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"mv", "write", "code", "design", "code"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code", "design", "code"},
+					},
+				},
+				WantStderr: "item \"design\" does not exist\n",
+				WantErr:    fmt.Errorf(`item "design" does not exist`),
+			},
+		},
+		{
+			name: "move errors if destination already exists",
+			l: &List{
+				Items: map[string]*Item{
+					"design": {
+						Items: map[string]*Item{
+							"code": {},
+						},
+					},
+					"write": {
+						Items: map[string]*Item{
+							"code": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"mv", "write", "code", "design"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code", "design"},
+					},
+				},
+				WantStderr: "item \"design\", \"code\" already exists\n",
+				WantErr:    fmt.Errorf(`item "design", "code" already exists`),
+			},
+		},
+		{
+			name: "move errors if moving item into itself",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"mv", "write", "write", "code"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "write", "code"},
+					},
+				},
+				WantStderr: "can't move an item into itself\n",
+				WantErr:    fmt.Errorf("can't move an item into itself"),
+			},
+		},
+		{
+			name: "renames primary and its format",
+			l: &List{
+				Items: map[string]*Item{
+					"wirte": {
+						Items: map[string]*Item{
+							"code": {Done: true},
+						},
+					},
+				},
+				PrimaryFormats: map[string]*color.Format{
+					"wirte": {
+						Color: color.Red,
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"mv", "wirte", "write"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"wirte", "write"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Order:   []string{"write"},
+				Items: map[string]*Item{
+					"write": {
+						Updated: testTime,
+						Items: map[string]*Item{
+							"code": {Done: true},
+						},
+					},
+				},
+				PrimaryFormats: map[string]*color.Format{
+					"write": {
+						Color: color.Red,
+					},
+				},
+			},
+		},
+		{
+			name: "renames secondary",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"cdoe": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"mv", "write", "cdoe", "write", "code"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "cdoe", "write", "code"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Order: []string{"code"},
+						Items: map[string]*Item{
+							"code": {Updated: testTime},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "moves secondary to another primary",
+			l: &List{
+				Items: map[string]*Item{
+					"design": {},
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Items: map[string]*Item{
+									"parser": {},
+								},
+							},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"mv", "write", "code", "design"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code", "design"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"design": {
+						Order: []string{"code"},
+						Items: map[string]*Item{
+							"code": {
+								Updated: testTime,
+								Items: map[string]*Item{
+									"parser": {},
+								},
+							},
+						},
+					},
+					"write": {
+						Items: map[string]*Item{},
+					},
+				},
+			},
+		},
+		{
+			name: "moves and renames secondary",
+			l: &List{
+				Items: map[string]*Item{
+					"design": {
+						Items: map[string]*Item{
+							"docs": {},
+						},
+					},
+					"write": {
+						Items: map[string]*Item{
+							"code": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"mv", "write", "code", "design", "prototype"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code", "design", "prototype"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"design": {
+						Order: []string{"docs", "prototype"},
+						Items: map[string]*Item{
+							"docs":      {},
+							"prototype": {Updated: testTime},
+						},
+					},
+					"write": {
+						Items: map[string]*Item{},
+					},
+				},
+			},
+		},
+		{
+			name: "moves primary under another primary and drops its format",
+			l: &List{
+				Items: map[string]*Item{
+					"design": {},
+					"write":  {},
+				},
+				PrimaryFormats: map[string]*color.Format{
+					"write": {
+						Color: color.Red,
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"mv", "write", "design"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "design"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"design": {
+						Order: []string{"write"},
+						Items: map[string]*Item{
+							"write": {Updated: testTime},
+						},
+					},
+				},
+				PrimaryFormats: map[string]*color.Format{},
+			},
+		},
+		// CompleteItem
+		{
+			name: "complete requires secondary",
+			etc: &command.ExecuteTestCase{
+				Args: []string{"c", "write"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write"},
+					},
+				},
+				WantStderr: "Argument \"path\" requires at least 2 arguments, got 1\n",
+				WantErr:    fmt.Errorf(`Argument "path" requires at least 2 arguments, got 1`),
+			},
+		},
+		{
+			name: "complete errors on unknown primary",
+			etc: &command.ExecuteTestCase{
+				Args: []string{"c", "write", "code"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code"},
+					},
+				},
+				WantStderr: "item \"write\" does not exist\n",
+				WantErr:    fmt.Errorf(`item "write" does not exist`),
+			},
+		},
+		{
+			name: "complete errors on unknown secondary",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"c", "write", "code"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code"},
+					},
+				},
+				WantStderr: "item \"write\", \"code\" does not exist\n",
+				WantErr:    fmt.Errorf(`item "write", "code" does not exist`),
+			},
+		},
+		{
+			name: "complete errors if already done",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {Done: true},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"c", "write", "code"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code"},
+					},
+				},
+				WantStderr: "item \"write\", \"code\" is already done\n",
+				WantErr:    fmt.Errorf(`item "write", "code" is already done`),
+			},
+		},
+		{
+			name: "completes item",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code":  {},
+							"tests": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"c", "write", "code"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code":  {Done: true, Updated: testTime},
+							"tests": {},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "completes nested item",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Items: map[string]*Item{
+									"parser": {},
+								},
+							},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"c", "write", "code", "parser"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code", "parser"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Items: map[string]*Item{
+									"parser": {Done: true, Updated: testTime},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		// UncompleteItem
+		{
+			name: "uncomplete errors if not done",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"u", "write", "code"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code"},
+					},
+				},
+				WantStderr: "item \"write\", \"code\" is not done\n",
+				WantErr:    fmt.Errorf(`item "write", "code" is not done`),
+			},
+		},
+		{
+			name: "uncompletes item",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code":  {Done: true},
+							"tests": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"u", "write", "code"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code":  {Updated: testTime},
+							"tests": {},
+						},
+					},
+				},
+			},
+		},
+		// SetNote
+		{
+			name: "note errors on unknown item",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"note", "sleep", "more"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"sleep", "more"},
+					},
+				},
+				WantStderr: "item \"sleep\" does not exist\n",
+				WantErr:    fmt.Errorf(`item "sleep" does not exist`),
+			},
+		},
+		{
+			name: "sets note on primary",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"note", "write", "for", "work"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "for", "work"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Updated: testTime,
+						Note:    "for work",
+						Items: map[string]*Item{
+							"code": {},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "sets multi-line note on secondary",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Note: "old",
+							},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"note", "write", "code", "--", "in go\nwith tests"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code", "--", "in go\nwith tests"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Updated: testTime,
+								Note:    "in go\nwith tests",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "clears note",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Note: "old",
+							},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"note", "write", "code"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Updated: testTime,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "does nothing if note is unchanged",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Note: "for work",
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"note", "write", "for", "work"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "for", "work"},
+					},
+				},
+			},
+		},
+		{
+			name: "note text after separator is never part of the path",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Items: map[string]*Item{
+									"tests": {},
+								},
+							},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"note", "write", "code", "--", "tests", "are", "flaky"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code", "--", "tests", "are", "flaky"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Updated: testTime,
+								Note:    "tests are flaky",
+								Items: map[string]*Item{
+									"tests": {},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "note text without separator starts after the longest existing path",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Items: map[string]*Item{
+									"tests": {},
+								},
+							},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"note", "write", "code", "tests", "are", "flaky"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code", "tests", "are", "flaky"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Items: map[string]*Item{
+									"tests": {
+										Updated: testTime,
+										Note:    "are flaky",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "note errors when no path is provided",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"note", "--", "write"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"--", "write"},
+					},
+				},
+				WantStderr: "no item provided before \"--\"\n",
+				WantErr:    fmt.Errorf(`no item provided before "--"`),
+			},
+		},
+		// SetDue
+		{
+			name: "due errors on unknown item",
+			etc: &command.ExecuteTestCase{
+				Args: []string{"due", "write", "fri"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "fri"},
+					},
+				},
+				WantStderr: "item \"write\" does not exist\n",
+				WantErr:    fmt.Errorf(`item "write" does not exist`),
+			},
+		},
+		{
+			name: "due errors on invalid date",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"due", "write", "eventually"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "eventually"},
+					},
+				},
+				WantStderr: "invalid due date \"eventually\"\n",
+				WantErr:    fmt.Errorf(`invalid due date "eventually"`),
+			},
+		},
+		{
+			name: "sets due date",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"due", "write", "code", "+3d"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code", "+3d"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Updated: testTime,
+								Due:     time.Date(2023, time.February, 6, 0, 0, 0, 0, time.UTC),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "clears due date",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Due: testTime,
+							},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"due", "write", "code", "none"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code", "none"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Updated: testTime,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "due date is the last argument even if it names a sub-item",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Items: map[string]*Item{
+									"fri": {},
+								},
+							},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"due", "write", "code", "fri"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code", "fri"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Updated: testTime,
+								Due:     time.Date(2023, time.February, 3, 0, 0, 0, 0, time.UTC),
+								Items: map[string]*Item{
+									"fri": {},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "due errors without a date",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"due", "write"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write"},
+					},
+				},
+				WantStderr: "expected the path of an item followed by a value; got [write]\n",
+				WantErr:    fmt.Errorf("expected the path of an item followed by a value; got [write]"),
+			},
+		},
+		// SetPriority
+		{
+			name: "priority errors on unknown item",
+			etc: &command.ExecuteTestCase{
+				Args: []string{"p", "write", "P1"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "P1"},
+					},
+				},
+				WantStderr: "item \"write\" does not exist\n",
+				WantErr:    fmt.Errorf(`item "write" does not exist`),
+			},
+		},
+		{
+			name: "priority errors on invalid priority",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"p", "write", "urgent"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "urgent"},
+					},
+				},
+				WantStderr: "invalid priority \"urgent\"; must be one of [P0, P1, P2, P3]\n",
+				WantErr:    fmt.Errorf(`invalid priority "urgent"; must be one of [P0, P1, P2, P3]`),
+			},
+		},
+		{
+			name: "priority errors on multiple priorities",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"p", "write", "P1", "P2"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "P1", "P2"},
+					},
+				},
+				WantStderr: "item \"write\", \"P1\" does not exist\n",
+				WantErr:    fmt.Errorf(`item "write", "P1" does not exist`),
+			},
+		},
+		{
+			name: "sets priority",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"p", "write", "code", "p1"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code", "p1"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Updated:  testTime,
+								Priority: "P1",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "clears priority",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Priority: "P0",
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"p", "write", "none"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "none"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Updated: testTime,
+					},
+				},
+			},
+		},
+		// OrderItem
+		{
+			name: "order errors on unknown item",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"order", "sleep", "up"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"sleep", "up"},
+					},
+				},
+				WantStderr: "item \"sleep\" does not exist\n",
+				WantErr:    fmt.Errorf(`item "sleep" does not exist`),
+			},
+		},
+		{
+			name: "order errors on invalid position",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"order", "write", "sideways"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "sideways"},
+					},
+				},
+				WantStderr: "invalid position \"sideways\"; must be a positive integer or one of [up down top bottom]\n",
+				WantErr:    fmt.Errorf(`invalid position "sideways"; must be a positive integer or one of [up down top bottom]`),
+			},
+		},
+		{
+			name: "order errors on multiple positions",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"order", "write", "up", "up"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "up", "up"},
+					},
+				},
+				WantStderr: "item \"write\", \"up\" does not exist\n",
+				WantErr:    fmt.Errorf(`item "write", "up" does not exist`),
+			},
+		},
+		{
+			name: "moves item up",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Order: []string{"a", "b", "c", "d"},
+						Items: map[string]*Item{
+							"a": {},
+							"b": {},
+							"c": {},
+							"d": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"order", "write", "c", "up"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "c", "up"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Order: []string{"a", "c", "b", "d"},
+						Items: map[string]*Item{
+							"a": {},
+							"b": {},
+							"c": {},
+							"d": {},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "moves item down",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Order: []string{"a", "b", "c", "d"},
+						Items: map[string]*Item{
+							"a": {},
+							"b": {},
+							"c": {},
+							"d": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"order", "write", "c", "down"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "c", "down"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Order: []string{"a", "b", "d", "c"},
+						Items: map[string]*Item{
+							"a": {},
+							"b": {},
+							"c": {},
+							"d": {},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "moves item to top",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Order: []string{"a", "b", "c", "d"},
+						Items: map[string]*Item{
+							"a": {},
+							"b": {},
+							"c": {},
+							"d": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"order", "write", "c", "top"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "c", "top"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Order: []string{"c", "a", "b", "d"},
+						Items: map[string]*Item{
+							"a": {},
+							"b": {},
+							"c": {},
+							"d": {},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "moves item to bottom",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Order: []string{"a", "b", "c", "d"},
+						Items: map[string]*Item{
+							"a": {},
+							"b": {},
+							"c": {},
+							"d": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"order", "write", "c", "bottom"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "c", "bottom"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Order: []string{"a", "b", "d", "c"},
+						Items: map[string]*Item{
+							"a": {},
+							"b": {},
+							"c": {},
+							"d": {},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "moves item to index",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Order: []string{"a", "b", "c", "d"},
+						Items: map[string]*Item{
+							"a": {},
+							"b": {},
+							"c": {},
+							"d": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"order", "write", "c", "2"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "c", "2"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Order: []string{"a", "c", "b", "d"},
+						Items: map[string]*Item{
+							"a": {},
+							"b": {},
+							"c": {},
+							"d": {},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "moves item to index past the end",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Order: []string{"a", "b", "c", "d"},
+						Items: map[string]*Item{
+							"a": {},
+							"b": {},
+							"c": {},
+							"d": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"order", "write", "c", "9"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "c", "9"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Order: []string{"a", "b", "d", "c"},
+						Items: map[string]*Item{
+							"a": {},
+							"b": {},
+							"c": {},
+							"d": {},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "orders unordered items alphabetically first",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Order: []string{"d"},
+						Items: map[string]*Item{
+							"a": {},
+							"b": {},
+							"c": {},
+							"d": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"order", "write", "c", "top"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "c", "top"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Order: []string{"c", "d", "a", "b"},
+						Items: map[string]*Item{
+							"a": {},
+							"b": {},
+							"c": {},
+							"d": {},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "orders primary items",
+			l: &List{
+				Items: map[string]*Item{
+					"sleep": {},
+					"write": {},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"order", "write", "1"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "1"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Order:   []string{"write", "sleep"},
+				Items: map[string]*Item{
+					"sleep": {},
+					"write": {},
+				},
+			},
+		},
+		{
+			name: "order position is the last argument even if it names a sub-item",
+			l: &List{
+				Items: map[string]*Item{
+					"sleep": {},
+					"write": {
+						Items: map[string]*Item{
+							"top": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"order", "write", "top"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "top"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Order:   []string{"write", "sleep"},
+				Items: map[string]*Item{
+					"sleep": {},
+					"write": {
+						Items: map[string]*Item{
+							"top": {},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "delete removes item from order",
+			l: &List{
+				Order: []string{"write", "sleep"},
+				Items: map[string]*Item{
+					"sleep": {},
+					"write": {},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"d", "write"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Order:   []string{"sleep"},
+				Items: map[string]*Item{
+					"sleep": {},
+				},
+			},
+		},
+		// Search
+		{
+			name: "search matches substrings",
+			l: &List{
+				Order: []string{"write", "read"},
+				Items: map[string]*Item{
+					"read": {
+						Items: map[string]*Item{
+							"Go spec": {Note: "skim the\nmemory model"},
+						},
+					},
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Done: true,
+								Items: map[string]*Item{
+									"go parser": {},
+									"cli":       {},
+								},
+							},
+							"tests": {Note: "table tests for gofmt"},
+						},
+					},
+				},
+				PrimaryFormats: map[string]*color.Format{
+					"write": {
+						Color: color.Blue,
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"s", "go"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						patternArg: "go",
+					},
+				},
+				WantStdout: strings.Join([]string{
+					color.Blue.Format("write"),
+					"  [x] code",
+					"    [ ] " + matchFormat.Format("go") + " parser",
+					"  [ ] tests",
+					"      > table tests for " + matchFormat.Format("go") + "fmt",
+					"",
+				}, "\n"),
+			},
+		},
+		{
+			name: "search ignores case",
+			l: &List{
+				Order: []string{"write", "read"},
+				Items: map[string]*Item{
+					"read": {
+						Items: map[string]*Item{
+							"Go spec": {Note: "skim the\nmemory model"},
+						},
+					},
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Done: true,
+								Items: map[string]*Item{
+									"go parser": {},
+									"cli":       {},
+								},
+							},
+							"tests": {Note: "table tests for gofmt"},
+						},
+					},
+				},
+				PrimaryFormats: map[string]*color.Format{
+					"write": {
+						Color: color.Blue,
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"s", "go", "-i"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						patternArg:     "go",
+						ignoreCaseFlag: true,
+					},
+				},
+				WantStdout: strings.Join([]string{
+					color.Blue.Format("write"),
+					"  [x] code",
+					"    [ ] " + matchFormat.Format("go") + " parser",
+					"  [ ] tests",
+					"      > table tests for " + matchFormat.Format("go") + "fmt",
+					"read",
+					"  [ ] " + matchFormat.Format("Go") + " spec",
+					"",
+				}, "\n"),
+			},
+		},
+		{
+			name: "search matches notes",
+			l: &List{
+				Order: []string{"write", "read"},
+				Items: map[string]*Item{
+					"read": {
+						Items: map[string]*Item{
+							"Go spec": {Note: "skim the\nmemory model"},
+						},
+					},
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Done: true,
+								Items: map[string]*Item{
+									"go parser": {},
+									"cli":       {},
+								},
+							},
+							"tests": {Note: "table tests for gofmt"},
+						},
+					},
+				},
+				PrimaryFormats: map[string]*color.Format{
+					"write": {
+						Color: color.Blue,
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"s", "memory"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						patternArg: "memory",
+					},
+				},
+				WantStdout: strings.Join([]string{
+					"read",
+					"  [ ] Go spec",
+					"      > " + matchFormat.Format("memory") + " model",
+					"",
+				}, "\n"),
+			},
+		},
+		{
+			name: "search highlights primaries",
+			l: &List{
+				Order: []string{"write", "read"},
+				Items: map[string]*Item{
+					"read": {
+						Items: map[string]*Item{
+							"Go spec": {Note: "skim the\nmemory model"},
+						},
+					},
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Done: true,
+								Items: map[string]*Item{
+									"go parser": {},
+									"cli":       {},
+								},
+							},
+							"tests": {Note: "table tests for gofmt"},
+						},
+					},
+				},
+				PrimaryFormats: map[string]*color.Format{
+					"write": {
+						Color: color.Blue,
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"s", "rit"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						patternArg: "rit",
+					},
+				},
+				WantStdout: strings.Join([]string{
+					"w" + matchFormat.Format("rit") + "e",
+					"",
+				}, "\n"),
+			},
+		},
+		{
+			name: "search treats pattern literally",
+			l: &List{
+				Order: []string{"write", "read"},
+				Items: map[string]*Item{
+					"read": {
+						Items: map[string]*Item{
+							"Go spec": {Note: "skim the\nmemory model"},
+						},
+					},
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Done: true,
+								Items: map[string]*Item{
+									"go parser": {},
+									"cli":       {},
+								},
+							},
+							"tests": {Note: "table tests for gofmt"},
+						},
+					},
+				},
+				PrimaryFormats: map[string]*color.Format{
+					"write": {
+						Color: color.Blue,
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"s", "t.sts"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						patternArg: "t.sts",
+					},
+				},
+			},
+		},
+		{
+			name: "search supports regexes",
+			l: &List{
+				Order: []string{"write", "read"},
+				Items: map[string]*Item{
+					"read": {
+						Items: map[string]*Item{
+							"Go spec": {Note: "skim the\nmemory model"},
+						},
+					},
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Done: true,
+								Items: map[string]*Item{
+									"go parser": {},
+									"cli":       {},
+								},
+							},
+							"tests": {Note: "table tests for gofmt"},
+						},
+					},
+				},
+				PrimaryFormats: map[string]*color.Format{
+					"write": {
+						Color: color.Blue,
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"s", "--regex", "^t.sts|^c"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						patternArg: "^t.sts|^c",
+						regexFlag:  true,
+					},
+				},
+				WantStdout: strings.Join([]string{
+					color.Blue.Format("write"),
+					"  [x] " + matchFormat.Format("c") + "ode",
+					"    [ ] " + matchFormat.Format("c") + "li",
+					"  [ ] " + matchFormat.Format("tests"),
+					"",
+				}, "\n"),
+			},
+		},
+		{
+			name: "search errors on invalid regex",
+			l: &List{
+				Order: []string{"write", "read"},
+				Items: map[string]*Item{
+					"read": {
+						Items: map[string]*Item{
+							"Go spec": {Note: "skim the\nmemory model"},
+						},
+					},
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Done: true,
+								Items: map[string]*Item{
+									"go parser": {},
+									"cli":       {},
+								},
+							},
+							"tests": {Note: "table tests for gofmt"},
+						},
+					},
+				},
+				PrimaryFormats: map[string]*color.Format{
+					"write": {
+						Color: color.Blue,
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"s", "-r", "("},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						patternArg: "(",
+						regexFlag:  true,
+					},
+				},
+				WantStderr: "invalid regex \"(\": error parsing regexp: missing closing ): `(`\n",
+				WantErr:    fmt.Errorf("invalid regex \"(\": error parsing regexp: missing closing ): `(`"),
+			},
+		},
+		// TagItem
+		{
+			name: "tag errors on unknown item",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"tag", "sleep", "@home"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"sleep", "@home"},
+					},
+				},
+				WantStderr: "item \"sleep\" does not exist\n",
+				WantErr:    fmt.Errorf(`item "sleep" does not exist`),
+			},
+		},
+		{
+			name: "tag errors if no tags provided",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"tag", "write", "code"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code"},
+					},
+				},
+				WantStderr: "no tags provided\n",
+				WantErr:    fmt.Errorf("no tags provided"),
+			},
+		},
+		{
+			name: "adds tags",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Tags: []string{"@desk"},
+						Items: map[string]*Item{
+							"code": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"tag", "write", "@laptop", "@desk", "@coffee"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "@laptop", "@desk", "@coffee"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Updated: testTime,
+						Tags:    []string{"@coffee", "@desk", "@laptop"},
+						Items: map[string]*Item{
+							"code": {},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "adding existing tags is a no-op",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Tags: []string{"@desk"},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"tag", "write", "--", "@desk"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "--", "@desk"},
+					},
+				},
+			},
+		},
+		// UntagItem
+		{
+			name: "removes tags",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {Tags: []string{"@coffee", "@desk", "@laptop"}},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"untag", "write", "code", "@laptop", "@coffee"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code", "@laptop", "@coffee"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Updated: testTime,
+								Tags:    []string{"@desk"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "tag separator allows tags named like sub-items",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"home": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"tag", "write", "--", "home"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "--", "home"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Updated: testTime,
+						Tags:    []string{"home"},
+						Items: map[string]*Item{
+							"home": {},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "untag errors if item doesn't have tag",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Tags: []string{"@desk"},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"untag", "write", "@desk", "@home"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "@desk", "@home"},
+					},
+				},
+				WantStderr: "item \"write\" is not tagged with \"@home\"\n",
+				WantErr:    fmt.Errorf(`item "write" is not tagged with "@home"`),
+			},
+		},
+		// ArchiveItem
+		{
+			name: "archive errors on unknown item",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"archive", "write", "code"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code"},
+					},
+				},
+				WantStderr: "item \"write\", \"code\" does not exist\n",
+				WantErr:    fmt.Errorf(`item "write", "code" does not exist`),
+			},
+		},
+		{
+			name: "archives sub-item",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Order: []string{"tests", "code"},
+						Items: map[string]*Item{
+							"code": {
+								Done: true,
+								Items: map[string]*Item{
+									"parser": {},
+								},
+							},
+							"tests": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"archive", "write", "code"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Order: []string{"tests"},
+						Items: map[string]*Item{
+							"tests": {},
+						},
+					},
+				},
+				Archive: []*ArchivedItem{
+					{
+						Path: []string{"write", "code"},
+						Item: &Item{
+							Done: true,
+							Items: map[string]*Item{
+								"parser": {},
+							},
+						},
+						Archived: testTime,
+					},
+				},
+			},
+		},
+		{
+			name: "archives primary item with its format",
+			l: &List{
+				Order: []string{"write"},
+				Items: map[string]*Item{
+					"write": {},
+				},
+				PrimaryFormats: map[string]*color.Format{
+					"write": {Color: color.Red},
+				},
+				Archive: []*ArchivedItem{
+					{
+						Path:     []string{"sleep"},
+						Item:     &Item{},
+						Archived: testTime,
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"archive", "write"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write"},
+					},
+				},
+			},
+			want: &List{
+				changed:        true,
+				Items:          map[string]*Item{},
+				PrimaryFormats: map[string]*color.Format{},
+				Archive: []*ArchivedItem{
+					{
+						Path:     []string{"sleep"},
+						Item:     &Item{},
+						Archived: testTime,
+					},
+					{
+						Path:     []string{"write"},
+						Item:     &Item{},
+						Format:   &color.Format{Color: color.Red},
+						Archived: testTime,
+					},
+				},
+			},
+		},
+		// ListArchive
+		{
+			name: "lists archive",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+				Archive: []*ArchivedItem{
+					{
+						Path:     []string{"write", "code"},
+						Item:     &Item{Done: true, Priority: "P1"},
+						Archived: time.Date(2023, time.January, 27, 9, 0, 0, 0, time.UTC),
+					},
+					{
+						Path: []string{"sleep"},
+						Item: &Item{
+							Items: map[string]*Item{
+								"nap": {Done: true},
+							},
+						},
+						Archived: time.Date(2023, time.February, 1, 9, 0, 0, 0, time.UTC),
+					},
+					{
+						Path:     []string{"write", "tests"},
+						Item:     &Item{},
+						Archived: time.Date(2023, time.February, 3, 1, 0, 0, 0, time.UTC),
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"archive", "ls"},
+				WantStdout: strings.Join([]string{
+					"2023-01-27 09:00 [x] write > code [P1]",
+					"2023-02-01 09:00 [ ] sleep",
+					"  [x] nap",
+					"2023-02-03 01:00 [ ] write > tests",
+					"",
+				}, "\n"),
+			},
+		},
+		{
+			name: "lists archive since date",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+				Archive: []*ArchivedItem{
+					{
+						Path:     []string{"write", "code"},
+						Item:     &Item{Done: true, Priority: "P1"},
+						Archived: time.Date(2023, time.January, 27, 9, 0, 0, 0, time.UTC),
+					},
+					{
+						Path: []string{"sleep"},
+						Item: &Item{
+							Items: map[string]*Item{
+								"nap": {Done: true},
+							},
+						},
+						Archived: time.Date(2023, time.February, 1, 9, 0, 0, 0, time.UTC),
+					},
+					{
+						Path:     []string{"write", "tests"},
+						Item:     &Item{},
+						Archived: time.Date(2023, time.February, 3, 1, 0, 0, 0, time.UTC),
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"archive", "ls", "--since", "2023-02-01"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						sinceFlag: "2023-02-01",
+					},
+				},
+				WantStdout: strings.Join([]string{
+					"2023-02-01 09:00 [ ] sleep",
+					"  [x] nap",
+					"2023-02-03 01:00 [ ] write > tests",
+					"",
+				}, "\n"),
+			},
+		},
+		{
+			name: "lists archive until date",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+				Archive: []*ArchivedItem{
+					{
+						Path:     []string{"write", "code"},
+						Item:     &Item{Done: true, Priority: "P1"},
+						Archived: time.Date(2023, time.January, 27, 9, 0, 0, 0, time.UTC),
+					},
+					{
+						Path: []string{"sleep"},
+						Item: &Item{
+							Items: map[string]*Item{
+								"nap": {Done: true},
+							},
+						},
+						Archived: time.Date(2023, time.February, 1, 9, 0, 0, 0, time.UTC),
+					},
+					{
+						Path:     []string{"write", "tests"},
+						Item:     &Item{},
+						Archived: time.Date(2023, time.February, 3, 1, 0, 0, 0, time.UTC),
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"archive", "ls", "-u", "yesterday"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						untilFlag: "yesterday",
+					},
+				},
+				WantStdout: strings.Join([]string{
+					"2023-01-27 09:00 [x] write > code [P1]",
+					"2023-02-01 09:00 [ ] sleep",
+					"  [x] nap",
+					"",
+				}, "\n"),
+			},
+		},
+		{
+			name: "lists archive in date range",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+				Archive: []*ArchivedItem{
+					{
+						Path:     []string{"write", "code"},
+						Item:     &Item{Done: true, Priority: "P1"},
+						Archived: time.Date(2023, time.January, 27, 9, 0, 0, 0, time.UTC),
+					},
+					{
+						Path: []string{"sleep"},
+						Item: &Item{
+							Items: map[string]*Item{
+								"nap": {Done: true},
+							},
+						},
+						Archived: time.Date(2023, time.February, 1, 9, 0, 0, 0, time.UTC),
+					},
+					{
+						Path:     []string{"write", "tests"},
+						Item:     &Item{},
+						Archived: time.Date(2023, time.February, 3, 1, 0, 0, 0, time.UTC),
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"archive", "ls", "-s", "7d", "-u", "wed"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						sinceFlag: "7d",
+						untilFlag: "wed",
+					},
+				},
+				WantStdout: strings.Join([]string{
+					"2023-01-27 09:00 [x] write > code [P1]",
+					"2023-02-01 09:00 [ ] sleep",
+					"  [x] nap",
+					"",
+				}, "\n"),
+			},
+		},
+		{
+			name: "archive ls errors on invalid date",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+				Archive: []*ArchivedItem{
+					{
+						Path:     []string{"write", "code"},
+						Item:     &Item{Done: true, Priority: "P1"},
+						Archived: time.Date(2023, time.January, 27, 9, 0, 0, 0, time.UTC),
+					},
+					{
+						Path: []string{"sleep"},
+						Item: &Item{
+							Items: map[string]*Item{
+								"nap": {Done: true},
+							},
+						},
+						Archived: time.Date(2023, time.February, 1, 9, 0, 0, 0, time.UTC),
+					},
+					{
+						Path:     []string{"write", "tests"},
+						Item:     &Item{},
+						Archived: time.Date(2023, time.February, 3, 1, 0, 0, 0, time.UTC),
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"archive", "ls", "-s", "later"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						sinceFlag: "later",
+					},
+				},
+				WantStderr: "invalid date \"later\"\n",
+				WantErr:    fmt.Errorf(`invalid date "later"`),
+			},
+		},
+		{
+			name: "restore errors if item isn't archived",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+				Archive: []*ArchivedItem{
+					{
+						Path:     []string{"write", "code"},
+						Item:     &Item{Done: true, Priority: "P1"},
+						Archived: time.Date(2023, time.January, 27, 9, 0, 0, 0, time.UTC),
+					},
+					{
+						Path: []string{"sleep"},
+						Item: &Item{
+							Items: map[string]*Item{
+								"nap": {Done: true},
+							},
+						},
+						Archived: time.Date(2023, time.February, 1, 9, 0, 0, 0, time.UTC),
+					},
+					{
+						Path:     []string{"write", "tests"},
+						Item:     &Item{},
+						Archived: time.Date(2023, time.February, 3, 1, 0, 0, 0, time.UTC),
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"archive", "restore", "write", "docs"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "docs"},
+					},
+				},
+				WantStderr: "item \"write\", \"docs\" is not archived\n",
+				WantErr:    fmt.Errorf(`item "write", "docs" is not archived`),
+			},
+		},
+		{
+			name: "restore errors if item exists",
+			l: &List{
+				Items: map[string]*Item{
+					"sleep": {},
+				},
+				Archive: []*ArchivedItem{
+					{
+						Path:     []string{"sleep"},
+						Item:     &Item{Done: true},
+						Archived: testTime,
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"archive", "restore", "sleep"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"sleep"},
+					},
+				},
+				WantStderr: "item \"sleep\" already exists\n",
+				WantErr:    fmt.Errorf(`item "sleep" already exists`),
+			},
+		},
+		{
+			name: "restores most recently archived item",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Order: []string{"tests"},
+						Items: map[string]*Item{
+							"tests": {},
+						},
+					},
+				},
+				Archive: []*ArchivedItem{
+					{
+						Path:     []string{"write", "code"},
+						Item:     &Item{Note: "first"},
+						Archived: testTime,
+					},
+					{
+						Path:     []string{"sleep"},
+						Item:     &Item{},
+						Archived: testTime,
+					},
+					{
+						Path:     []string{"write", "code"},
+						Item:     &Item{Note: "second"},
+						Archived: testTime,
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"archive", "restore", "write", "code"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Order: []string{"tests", "code"},
+						Items: map[string]*Item{
+							"code":  {Note: "second"},
+							"tests": {},
+						},
+					},
+				},
+				Archive: []*ArchivedItem{
+					{
+						Path:     []string{"write", "code"},
+						Item:     &Item{Note: "first"},
+						Archived: testTime,
+					},
+					{
+						Path:     []string{"sleep"},
+						Item:     &Item{},
+						Archived: testTime,
+					},
+				},
+			},
+		},
+		{
+			name: "restore recreates missing parents and formats",
+			l: &List{
+				Archive: []*ArchivedItem{
+					{
+						Path:     []string{"write"},
+						Item:     &Item{},
+						Format:   &color.Format{Color: color.Red},
+						Archived: testTime,
+					},
+					{
+						Path:     []string{"sleep", "nap"},
+						Item:     &Item{Done: true},
+						Archived: testTime,
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"archive", "restore", "sleep", "nap"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"sleep", "nap"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Order:   []string{"sleep"},
+				Items: map[string]*Item{
+					"sleep": {
+						Created: testTime,
+						Updated: testTime,
+						Order:   []string{"nap"},
+						Items: map[string]*Item{
+							"nap": {Done: true},
+						},
+					},
+				},
+				Archive: []*ArchivedItem{
+					{
+						Path:     []string{"write"},
+						Item:     &Item{},
+						Format:   &color.Format{Color: color.Red},
+						Archived: testTime,
+					},
+				},
+			},
+		},
+		{
+			name: "restores primary format",
+			l: &List{
+				Archive: []*ArchivedItem{
+					{
+						Path:     []string{"write"},
+						Item:     &Item{},
+						Format:   &color.Format{Color: color.Red},
+						Archived: testTime,
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"archive", "restore", "write"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write"},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Order:   []string{"write"},
+				Items: map[string]*Item{
+					"write": {},
 				},
+				PrimaryFormats: map[string]*color.Format{
+					"write": {Color: color.Red},
+				},
+				Archive: []*ArchivedItem{},
 			},
 		},
 		// FormatPrimary
 		{
 			name: "successfully adds format",
 			l: &List{
-				Items: map[string]map[string]bool{
+				Items: map[string]*Item{
 					"write": {
-						"code":  true,
-						"tests": true,
+						Items: map[string]*Item{
+							"code":  {},
+							"tests": {},
+						},
 					},
 				},
 			},
@@ -471,10 +4456,12 @@ func TestExecution(t *testing.T) {
 			},
 			want: &List{
 				changed: true,
-				Items: map[string]map[string]bool{
+				Items: map[string]*Item{
 					"write": {
-						"code":  true,
-						"tests": true,
+						Items: map[string]*Item{
+							"code":  {},
+							"tests": {},
+						},
 					},
 				},
 				PrimaryFormats: map[string]*color.Format{
@@ -488,10 +4475,12 @@ func TestExecution(t *testing.T) {
 		{
 			name: "successfully updates format",
 			l: &List{
-				Items: map[string]map[string]bool{
+				Items: map[string]*Item{
 					"write": {
-						"code":  true,
-						"tests": true,
+						Items: map[string]*Item{
+							"code":  {},
+							"tests": {},
+						},
 					},
 				},
 				PrimaryFormats: map[string]*color.Format{
@@ -512,10 +4501,12 @@ func TestExecution(t *testing.T) {
 			},
 			want: &List{
 				changed: true,
-				Items: map[string]map[string]bool{
+				Items: map[string]*Item{
 					"write": {
-						"code":  true,
-						"tests": true,
+						Items: map[string]*Item{
+							"code":  {},
+							"tests": {},
+						},
 					},
 				},
 				PrimaryFormats: map[string]*color.Format{
@@ -526,49 +4517,676 @@ func TestExecution(t *testing.T) {
 			},
 		},
 		{
-			name: "error with format",
-			l: &List{
-				Items: map[string]map[string]bool{
-					"write": {
-						"code":  true,
-						"tests": true,
-					},
+			name: "error with format",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code":  {},
+							"tests": {},
+						},
+					},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"f", "write", "crazy"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						primaryArg:    "write",
+						color.ArgName: []string{"crazy"},
+					},
+				},
+				WantStderr: "invalid attribute: crazy\n",
+				WantErr:    fmt.Errorf("invalid attribute: crazy"),
+			},
+		},
+		{
+			name: "format errors on unknown primary",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+			},
+			etc: &command.ExecuteTestCase{
+				Args: []string{"f", "wirte", "bold"},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						primaryArg:    "wirte",
+						color.ArgName: []string{"bold"},
+					},
+				},
+				WantStderr: "item \"wirte\" does not exist\n",
+				WantErr:    fmt.Errorf(`item "wirte" does not exist`),
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if test.l == nil {
+				test.l = &List{}
+			}
+			oldStdin := stdin
+			stdin = strings.NewReader(test.stdin)
+			defer func() { stdin = oldStdin }()
+			stubNow(t, testTime)
+
+			test.etc.Node = test.l.Node()
+			command.ExecuteTest(t, test.etc)
+			command.ChangeTest(t, test.want, test.l, cmp.AllowUnexported(List{}), ignoreHistory, ignoreLog)
+		})
+	}
+}
+
+var (
+	// ignoreHistory ignores undo history, which is verified in TestHistory.
+	ignoreHistory = cmpopts.IgnoreFields(List{}, "UndoStack", "RedoStack")
+	// ignoreLog ignores the event log, which is verified in TestLog.
+	ignoreLog = cmpopts.IgnoreFields(List{}, "Log")
+)
+
+func TestHistory(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		l        *List
+		steps    []*command.ExecuteTestCase
+		want     *List
+		wantUndo int
+		wantRedo int
+	}{
+		{
+			name: "errors if nothing to undo",
+			steps: []*command.ExecuteTestCase{
+				{
+					Args:       []string{"undo"},
+					WantStderr: "nothing to undo\n",
+					WantErr:    fmt.Errorf("nothing to undo"),
+				},
+			},
+		},
+		{
+			name: "errors if nothing to redo",
+			steps: []*command.ExecuteTestCase{
+				{
+					Args:       []string{"redo"},
+					WantStderr: "nothing to redo\n",
+					WantErr:    fmt.Errorf("nothing to redo"),
+				},
+			},
+		},
+		{
+			name: "errors on non-positive count",
+			steps: []*command.ExecuteTestCase{
+				{
+					Args: []string{"undo", "0"},
+					WantData: &command.Data{
+						Values: map[string]interface{}{
+							countArg: 0,
+						},
+					},
+					WantStderr: "number of operations must be positive\n",
+					WantErr:    fmt.Errorf("number of operations must be positive"),
+				},
+			},
+		},
+		{
+			name: "failed operations are not recorded",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {},
+				},
+			},
+			steps: []*command.ExecuteTestCase{
+				{
+					Args: []string{"a", "write"},
+					WantData: &command.Data{
+						Values: map[string]interface{}{
+							pathArg: []string{"write"},
+						},
+					},
+					WantStderr: "item \"write\" already exists\n",
+					WantErr:    fmt.Errorf(`item "write" already exists`),
+				},
+			},
+		},
+		{
+			name: "undoes add",
+			steps: []*command.ExecuteTestCase{
+				{
+					Args: []string{"a", "write", "code"},
+					WantData: &command.Data{
+						Values: map[string]interface{}{
+							pathArg: []string{"write", "code"},
+						},
+					},
+				},
+				{
+					Args: []string{"undo"},
+				},
+			},
+			want: &List{
+				changed: true,
+			},
+			wantRedo: 1,
+		},
+		{
+			name: "restores deleted item and format",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {Done: true},
+						},
+					},
+				},
+			},
+			steps: []*command.ExecuteTestCase{
+				{
+					Args: []string{"f", "write", "bold"},
+					WantData: &command.Data{
+						Values: map[string]interface{}{
+							primaryArg:    "write",
+							color.ArgName: []string{"bold"},
+						},
+					},
+				},
+				{
+					Args: []string{"d", "write", "code"},
+					WantData: &command.Data{
+						Values: map[string]interface{}{
+							pathArg: []string{"write", "code"},
+						},
+					},
+				},
+				{
+					Args: []string{"mv", "write", "words"},
+					WantData: &command.Data{
+						Values: map[string]interface{}{
+							pathArg: []string{"write", "words"},
+						},
+					},
+				},
+				{
+					Args: []string{"undo", "2"},
+					WantData: &command.Data{
+						Values: map[string]interface{}{
+							countArg: 2,
+						},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {Done: true},
+						},
+					},
+				},
+				PrimaryFormats: map[string]*color.Format{
+					"write": {
+						Thickness: color.Bold,
+					},
+				},
+			},
+			wantUndo: 1,
+			wantRedo: 2,
+		},
+		{
+			name: "undo count is capped at history size",
+			steps: []*command.ExecuteTestCase{
+				{
+					Args: []string{"a", "write"},
+					WantData: &command.Data{
+						Values: map[string]interface{}{
+							pathArg: []string{"write"},
+						},
+					},
+				},
+				{
+					Args: []string{"undo", "3"},
+					WantData: &command.Data{
+						Values: map[string]interface{}{
+							countArg: 3,
+						},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+			},
+			wantRedo: 1,
+		},
+		{
+			name: "redoes undone operations",
+			steps: []*command.ExecuteTestCase{
+				{
+					Args: []string{"a", "write"},
+					WantData: &command.Data{
+						Values: map[string]interface{}{
+							pathArg: []string{"write"},
+						},
+					},
+				},
+				{
+					Args: []string{"a", "write", "code"},
+					WantData: &command.Data{
+						Values: map[string]interface{}{
+							pathArg: []string{"write", "code"},
+						},
+					},
+				},
+				{
+					Args: []string{"undo", "2"},
+					WantData: &command.Data{
+						Values: map[string]interface{}{
+							countArg: 2,
+						},
+					},
+				},
+				{
+					Args: []string{"redo"},
+				},
+			},
+			want: &List{
+				changed: true,
+				Order:   []string{"write"},
+				Items: map[string]*Item{
+					"write": {
+						Created: testTime,
+						Updated: testTime,
+					},
+				},
+			},
+			wantUndo: 1,
+			wantRedo: 1,
+		},
+		{
+			name: "undoes archive changes",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code":  {},
+							"tests": {},
+						},
+					},
+				},
+				Archive: []*ArchivedItem{
+					{Path: []string{"sleep"}, Item: &Item{}},
+				},
+			},
+			steps: []*command.ExecuteTestCase{
+				{
+					Args: []string{"archive", "write", "code"},
+					WantData: &command.Data{
+						Values: map[string]interface{}{
+							pathArg: []string{"write", "code"},
+						},
+					},
+				},
+				{
+					Args: []string{"archive", "write", "tests"},
+					WantData: &command.Data{
+						Values: map[string]interface{}{
+							pathArg: []string{"write", "tests"},
+						},
+					},
+				},
+				{
+					Args: []string{"archive", "restore", "write", "code"},
+					WantData: &command.Data{
+						Values: map[string]interface{}{
+							pathArg: []string{"write", "code"},
+						},
+					},
+				},
+				{
+					Args: []string{"undo", "2"},
+					WantData: &command.Data{
+						Values: map[string]interface{}{
+							countArg: 2,
+						},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"tests": {},
+						},
+					},
+				},
+				Archive: []*ArchivedItem{
+					{Path: []string{"sleep"}, Item: &Item{}},
+					{Path: []string{"write", "code"}, Item: &Item{}, Archived: testTime},
+				},
+			},
+			wantUndo: 1,
+			wantRedo: 2,
+		},
+		{
+			name: "redoes archive changes",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code":  {},
+							"tests": {},
+						},
+					},
+				},
+				Archive: []*ArchivedItem{
+					{Path: []string{"sleep"}, Item: &Item{}},
+				},
+			},
+			steps: []*command.ExecuteTestCase{
+				{
+					Args: []string{"archive", "write", "code"},
+					WantData: &command.Data{
+						Values: map[string]interface{}{
+							pathArg: []string{"write", "code"},
+						},
+					},
+				},
+				{
+					Args: []string{"archive", "write", "tests"},
+					WantData: &command.Data{
+						Values: map[string]interface{}{
+							pathArg: []string{"write", "tests"},
+						},
+					},
+				},
+				{
+					Args: []string{"archive", "restore", "write", "code"},
+					WantData: &command.Data{
+						Values: map[string]interface{}{
+							pathArg: []string{"write", "code"},
+						},
+					},
+				},
+				{
+					Args: []string{"undo", "2"},
+					WantData: &command.Data{
+						Values: map[string]interface{}{
+							countArg: 2,
+						},
+					},
+				},
+				{
+					Args: []string{"redo", "2"},
+					WantData: &command.Data{
+						Values: map[string]interface{}{
+							countArg: 2,
+						},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Order: []string{"code"},
+						Items: map[string]*Item{
+							"code": {},
+						},
+					},
+				},
+				Archive: []*ArchivedItem{
+					{Path: []string{"sleep"}, Item: &Item{}},
+					{Path: []string{"write", "tests"}, Item: &Item{}, Archived: testTime},
+				},
+			},
+			wantUndo: 3,
+		},
+		{
+			name: "restores snapshots of the whole list from earlier versions",
+			l: &List{
+				Items: map[string]*Item{
+					"sleep": {},
+				},
+				UndoStack: []json.RawMessage{
+					json.RawMessage(`{"Items": {"write": {}}, "Archive": [{"Path": ["eat"], "Item": {}}]}`),
+				},
+			},
+			steps: []*command.ExecuteTestCase{
+				{
+					Args: []string{"undo"},
+				},
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {},
+				},
+				Archive: []*ArchivedItem{
+					{Path: []string{"eat"}, Item: &Item{}},
+				},
+			},
+			wantRedo: 1,
+		},
+		{
+			name: "new operation clears redo history",
+			steps: []*command.ExecuteTestCase{
+				{
+					Args: []string{"a", "write"},
+					WantData: &command.Data{
+						Values: map[string]interface{}{
+							pathArg: []string{"write"},
+						},
+					},
+				},
+				{
+					Args: []string{"undo"},
+				},
+				{
+					Args: []string{"a", "sleep"},
+					WantData: &command.Data{
+						Values: map[string]interface{}{
+							pathArg: []string{"sleep"},
+						},
+					},
+				},
+			},
+			want: &List{
+				changed: true,
+				Order:   []string{"sleep"},
+				Items: map[string]*Item{
+					"sleep": {
+						Created: testTime,
+						Updated: testTime,
+					},
+				},
+			},
+			wantUndo: 1,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if test.l == nil {
+				test.l = &List{}
+			}
+			stubNow(t, testTime)
+			for _, etc := range test.steps {
+				etc.Node = test.l.Node()
+				command.ExecuteTest(t, etc)
+			}
+			// Restoring a snapshot migrates it to the current version.
+			command.ChangeTest(t, test.want, test.l, cmp.AllowUnexported(List{}), ignoreHistory, ignoreLog, cmpopts.IgnoreFields(List{}, "Version"))
+			if got := len(test.l.UndoStack); got != test.wantUndo {
+				t.Errorf("len(UndoStack) = %d; want %d", got, test.wantUndo)
+			}
+			if got := len(test.l.RedoStack); got != test.wantRedo {
+				t.Errorf("len(RedoStack) = %d; want %d", got, test.wantRedo)
+			}
+		})
+	}
+}
+
+func TestHistoryIsBounded(t *testing.T) {
+	l := &List{}
+	for i := 0; i < maxHistory+5; i++ {
+		p := fmt.Sprintf("item-%d", i)
+		command.ExecuteTest(t, &command.ExecuteTestCase{
+			Node: l.Node(),
+			Args: []string{"a", p},
+			WantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{p},
+				},
+			},
+		})
+	}
+	if got := len(l.UndoStack); got != maxHistory {
+		t.Errorf("len(UndoStack) = %d; want %d", got, maxHistory)
+	}
+}
+
+func TestHistoryExcludesArchive(t *testing.T) {
+	stubNow(t, testTime)
+	l := &List{
+		Items: map[string]*Item{
+			"write": {},
+			"sleep": {},
+		},
+		Archive: []*ArchivedItem{
+			{Path: []string{"eat"}, Item: &Item{}},
+			{Path: []string{"drink"}, Item: &Item{}},
+		},
+	}
+	for _, etc := range []*command.ExecuteTestCase{
+		{
+			Args: []string{"a", "write", "code"},
+			WantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"write", "code"},
+				},
+			},
+		},
+		{
+			Args: []string{"archive", "sleep"},
+			WantData: &command.Data{
+				Values: map[string]interface{}{
+					pathArg: []string{"sleep"},
 				},
 			},
+		},
+		{
+			Args: []string{"undo"},
+		},
+	} {
+		etc.Node = l.Node()
+		command.ExecuteTest(t, etc)
+	}
+
+	// Entries only contain the archived items that an operation changed.
+	for _, raw := range append(l.UndoStack, l.RedoStack...) {
+		e := &historyEntry{}
+		if err := json.Unmarshal(raw, e); err != nil {
+			t.Fatalf("json.Unmarshal(entry) returned error: %v", err)
+		}
+		snap := &List{}
+		if err := json.Unmarshal(e.List, snap); err != nil {
+			t.Fatalf("json.Unmarshal(snapshot) returned error: %v", err)
+		}
+		if len(snap.Archive) != 0 {
+			t.Errorf("snapshot contains archive: %v", snap.Archive)
+		}
+		if e.Archive != nil && len(e.Archive.Added) > 1 {
+			t.Errorf("entry contains %d archived items; want at most 1", len(e.Archive.Added))
+		}
+	}
+}
+
+func TestHistoryRevertsLog(t *testing.T) {
+	stubNow(t, testTime)
+	l := &List{
+		Log: []*Event{
+			{Type: eventAdded, Path: []string{"sleep"}, Time: testTime},
+		},
+	}
+	added := []*Event{
+		{Type: eventAdded, Path: []string{"sleep"}, Time: testTime},
+		{Type: eventAdded, Path: []string{"write"}, Time: testTime},
+		{Type: eventAdded, Path: []string{"write", "code"}, Time: testTime},
+	}
+	for _, test := range []struct {
+		etc  *command.ExecuteTestCase
+		want []*Event
+	}{
+		{
 			etc: &command.ExecuteTestCase{
-				Args: []string{"f", "write", "crazy"},
+				Args: []string{"a", "write", "code"},
 				WantData: &command.Data{
 					Values: map[string]interface{}{
-						primaryArg:    "write",
-						color.ArgName: []string{"crazy"},
+						pathArg: []string{"write", "code"},
 					},
 				},
-				WantStderr: "invalid attribute: crazy\n",
-				WantErr:    fmt.Errorf("invalid attribute: crazy"),
 			},
+			want: added,
+		},
+		{
+			etc: &command.ExecuteTestCase{
+				Args: []string{"undo"},
+			},
+			want: added[:1],
+		},
+		{
+			etc: &command.ExecuteTestCase{
+				Args: []string{"redo"},
+			},
+			want: added,
 		},
 	} {
-		t.Run(test.name, func(t *testing.T) {
-			if test.l == nil {
-				test.l = &List{}
+		test.etc.Node = l.Node()
+		command.ExecuteTest(t, test.etc)
+		if diff := cmp.Diff(test.want, l.Log); diff != "" {
+			t.Errorf("%v: Log diff (-want, +got):\n%s", test.etc.Args, diff)
+		}
+
+		// Entries only contain the events that an operation changed.
+		for _, raw := range append(l.UndoStack, l.RedoStack...) {
+			e := &historyEntry{}
+			if err := json.Unmarshal(raw, e); err != nil {
+				t.Fatalf("json.Unmarshal(entry) returned error: %v", err)
 			}
-			test.etc.Node = test.l.Node()
-			command.ExecuteTest(t, test.etc)
-			command.ChangeTest(t, test.want, test.l, cmp.AllowUnexported(List{}))
-		})
+			snap := &List{}
+			if err := json.Unmarshal(e.List, snap); err != nil {
+				t.Fatalf("json.Unmarshal(snapshot) returned error: %v", err)
+			}
+			if len(snap.Log) != 0 {
+				t.Errorf("snapshot contains log: %v", snap.Log)
+			}
+			if e.Log != nil && len(e.Log.Added) > 2 {
+				t.Errorf("entry contains %d events; want at most 2", len(e.Log.Added))
+			}
+		}
 	}
 }
 
 func TestAutocomplete(t *testing.T) {
 	l := &List{
-		Items: map[string]map[string]bool{
+		Items: map[string]*Item{
 			"design": {
-				"solutions": true,
+				Items: map[string]*Item{
+					"solutions": {},
+				},
 			},
 			"write": {
-				"code":   false,
-				"tests":  true,
-				"things": false,
+				Items: map[string]*Item{
+					"code": {
+						Done: true,
+						Items: map[string]*Item{
+							"parser": {},
+							"cli":    {},
+						},
+					},
+					"tests": {},
+					"things": {
+						Done: true,
+						Tags: []string{"@home", "@desk"},
+					},
+				},
 			},
 		},
 		PrimaryFormats: map[string]*color.Format{
@@ -577,6 +5195,12 @@ func TestAutocomplete(t *testing.T) {
 				Thickness: color.Bold,
 			},
 		},
+		Archive: []*ArchivedItem{
+			{Path: []string{"write", "docs"}, Item: &Item{}},
+			{Path: []string{"write", "lint", "go"}, Item: &Item{}},
+			{Path: []string{"write", "docs"}, Item: &Item{}},
+			{Path: []string{"sleep"}, Item: &Item{}},
+		},
 	}
 
 	for _, test := range []struct {
@@ -588,8 +5212,32 @@ func TestAutocomplete(t *testing.T) {
 			ctc: &command.CompleteTestCase{
 				Want: []string{
 					"a",
+					"archive",
+					"block",
+					"c",
 					"d",
+					"due",
+					"export",
 					"f",
+					"import",
+					"mv",
+					"next",
+					"note",
+					"order",
+					"p",
+					"pomo",
+					"redo",
+					"repeat",
+					"report",
+					"s",
+					"start",
+					"stop",
+					"tag",
+					"time",
+					"u",
+					"unblock",
+					"undo",
+					"untag",
 				},
 			},
 		},
@@ -611,19 +5259,23 @@ func TestAutocomplete(t *testing.T) {
 				},
 				WantData: &command.Data{
 					Values: map[string]interface{}{
-						primaryArg: "",
+						pathArg: []string{""},
 					},
 				},
 			},
 		},
 		{
-			name: "add suggests no secondaries",
+			name: "add suggests existing secondaries",
 			ctc: &command.CompleteTestCase{
 				Args: "td a write ",
+				Want: []string{
+					"code",
+					"tests",
+					"things",
+				},
 				WantData: &command.Data{
 					Values: map[string]interface{}{
-						primaryArg:   "write",
-						secondaryArg: "",
+						pathArg: []string{"write", ""},
 					},
 				},
 			},
@@ -634,8 +5286,7 @@ func TestAutocomplete(t *testing.T) {
 				Args: "td a huh ",
 				WantData: &command.Data{
 					Values: map[string]interface{}{
-						primaryArg:   "huh",
-						secondaryArg: "",
+						pathArg: []string{"huh", ""},
 					},
 				},
 			},
@@ -651,7 +5302,7 @@ func TestAutocomplete(t *testing.T) {
 				},
 				WantData: &command.Data{
 					Values: map[string]interface{}{
-						primaryArg: "",
+						pathArg: []string{""},
 					},
 				},
 			},
@@ -667,8 +5318,22 @@ func TestAutocomplete(t *testing.T) {
 				},
 				WantData: &command.Data{
 					Values: map[string]interface{}{
-						primaryArg:   "write",
-						secondaryArg: "",
+						pathArg: []string{"write", ""},
+					},
+				},
+			},
+		},
+		{
+			name: "delete suggests nested items",
+			ctc: &command.CompleteTestCase{
+				Args: "td d write code ",
+				Want: []string{
+					"cli",
+					"parser",
+				},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "code", ""},
 					},
 				},
 			},
@@ -676,11 +5341,248 @@ func TestAutocomplete(t *testing.T) {
 		{
 			name: "delete handles unknown primary",
 			ctc: &command.CompleteTestCase{
-				Args: "td a huh ",
+				Args: "td d huh ",
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"huh", ""},
+					},
+				},
+			},
+		},
+		// MoveItem
+		{
+			name: "move suggests source primaries",
+			ctc: &command.CompleteTestCase{
+				Args: "td mv ",
+				Want: []string{
+					"design",
+					"write",
+				},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{""},
+					},
+				},
+			},
+		},
+		{
+			name: "move suggests source sub-items and destination primaries",
+			ctc: &command.CompleteTestCase{
+				Args: "td mv write ",
+				Want: []string{
+					"code",
+					"design",
+					"tests",
+					"things",
+					"write",
+				},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", ""},
+					},
+				},
+			},
+		},
+		{
+			name: "move suggests destination sub-items",
+			ctc: &command.CompleteTestCase{
+				Args: "td mv write tests design ",
+				Want: []string{
+					"solutions",
+				},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "tests", "design", ""},
+					},
+				},
+			},
+		},
+		{
+			name: "move suggests destination primaries after separator",
+			ctc: &command.CompleteTestCase{
+				Args: "td mv write -- ",
+				Want: []string{
+					"design",
+					"write",
+				},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "--", ""},
+					},
+				},
+			},
+		},
+		// CompleteItem
+		{
+			name: "complete suggests secondaries",
+			ctc: &command.CompleteTestCase{
+				Args: "td c write ",
+				Want: []string{
+					"code",
+					"tests",
+					"things",
+				},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", ""},
+					},
+				},
+			},
+		},
+		// SetDue
+		{
+			name: "due suggests sub-items and dates",
+			ctc: &command.CompleteTestCase{
+				Args: "td due write t",
+				Want: []string{
+					"tests",
+					"things",
+					"thu",
+					"today",
+					"tomorrow",
+					"tue",
+				},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "t"},
+					},
+				},
+			},
+		},
+		{
+			name: "due suggests nothing after date",
+			ctc: &command.CompleteTestCase{
+				Args: "td due write tomorrow ",
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "tomorrow", ""},
+					},
+				},
+			},
+		},
+		// SetPriority
+		{
+			name: "priority suggests priorities",
+			ctc: &command.CompleteTestCase{
+				Args: "td p write tests P",
+				Want: []string{
+					"P0",
+					"P1",
+					"P2",
+					"P3",
+				},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "tests", "P"},
+					},
+				},
+			},
+		},
+		// ListItems
+		{
+			name: "sort flag suggests sort options",
+			ctc: &command.CompleteTestCase{
+				Args: "td --sort ",
+				Want: []string{
+					"alpha",
+					"created",
+					"due",
+					"manual",
+					"priority",
+				},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						sortFlag: "",
+					},
+				},
+			},
+		},
+		{
+			name: "format flag suggests output formats",
+			ctc: &command.CompleteTestCase{
+				Args: "td --format ",
+				Want: []string{
+					"csv",
+					"json",
+					"text",
+					"tsv",
+					"yaml",
+				},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						outputFormatFlag: "",
+					},
+				},
+			},
+		},
+		// TagItem
+		{
+			name: "tag suggests sub-items and tags in use",
+			ctc: &command.CompleteTestCase{
+				Args: "td tag write tests ",
+				Want: []string{
+					"@desk",
+					"@home",
+				},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "tests", ""},
+					},
+				},
+			},
+		},
+		{
+			name: "tag suggests tags in use after separator",
+			ctc: &command.CompleteTestCase{
+				Args: "td tag write tests -- ",
+				Want: []string{
+					"@desk",
+					"@home",
+				},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						pathArg: []string{"write", "tests", "--", ""},
+					},
+				},
+			},
+		},
+		{
+			name: "tag flag suggests tags in use",
+			ctc: &command.CompleteTestCase{
+				Args: "td -t ",
+				Want: []string{
+					"@desk",
+					"@home",
+				},
+				WantData: &command.Data{
+					Values: map[string]interface{}{
+						tagFlag: "",
+					},
+				},
+			},
+		},
+		// ArchiveItem
+		{
+			name: "archive suggests subcommands",
+			ctc: &command.CompleteTestCase{
+				Args: "td archive ",
+				Want: []string{
+					"ls",
+					"restore",
+				},
+			},
+		},
+		{
+			name: "restore suggests archived paths",
+			ctc: &command.CompleteTestCase{
+				Args: "td archive restore write ",
+				Want: []string{
+					"docs",
+					"lint",
+				},
 				WantData: &command.Data{
 					Values: map[string]interface{}{
-						primaryArg:   "huh",
-						secondaryArg: "",
+						pathArg: []string{"write", ""},
 					},
 				},
 			},
@@ -742,3 +5644,61 @@ func TestMetadata(t *testing.T) {
 		t.Errorf("Incorrect todo list name: got %s; want %s", l.Name(), want)
 	}
 }
+
+func TestName(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		l    *List
+		want string
+	}{
+		{
+			name: "defaults to td",
+			l:    &List{},
+			want: "td",
+		},
+		{
+			name: "CLI is named td",
+			l:    CLI(),
+			want: "td",
+		},
+		{
+			name: "uses provided name",
+			l:    NamedCLI("tdw"),
+			want: "tdw",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.l.Name(); got != test.want {
+				t.Errorf("Name() returned %q; want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestNameIsKept(t *testing.T) {
+	l := NamedCLI("tdw")
+	if err := l.Load(""); err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if got := l.Name(); got != "tdw" {
+		t.Errorf("Name() after Load() returned %q; want %q", got, "tdw")
+	}
+
+	stubNow(t, testTime)
+	command.ExecuteTest(t, &command.ExecuteTestCase{
+		Node: l.Node(),
+		Args: []string{"a", "write"},
+		WantData: &command.Data{
+			Values: map[string]interface{}{
+				pathArg: []string{"write"},
+			},
+		},
+	})
+	command.ExecuteTest(t, &command.ExecuteTestCase{
+		Node: l.Node(),
+		Args: []string{"undo"},
+	})
+	if got := l.Name(); got != "tdw" {
+		t.Errorf("Name() after undo returned %q; want %q", got, "tdw")
+	}
+}
@@ -0,0 +1,208 @@
+package todo
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/leep-frog/command"
+)
+
+const (
+	// todoTxtTagKey is the key of the key-value pair used for tags that
+	// don't start with "@" (and so can't be written as contexts).
+	todoTxtTagKey = "tag:"
+)
+
+var (
+	todoTxtPriorityRegex = regexp.MustCompile(`^\(([A-Z])\)$`)
+
+	// Projects, contexts and key-value pairs can't contain whitespace, so it
+	// (along with the escape character itself) is percent-encoded.
+	todoTxtEscaper   = strings.NewReplacer("%", "%25", " ", "%20", "\t", "%09")
+	todoTxtUnescaper = strings.NewReplacer("%25", "%", "%20", " ", "%09", "\t")
+)
+
+// todoTxtPriority returns the todo.txt priority (A through D) of a priority.
+func todoTxtPriority(p string) string {
+	return string(rune('A' + priorityRank(p)))
+}
+
+// parseTodoTxtPriority returns the priority for a todo.txt priority letter.
+// Letters without a corresponding priority return an empty string.
+func parseTodoTxtPriority(letter string) string {
+	if len(letter) != 1 {
+		return ""
+	}
+	if i := int(letter[0] - 'A'); i >= 0 && i < len(priorities) {
+		return priorities[i]
+	}
+	return ""
+}
+
+// ExportTodoTxt outputs the list in the todo.txt format.
+func (tl *List) ExportTodoTxt(output command.Output, data *command.Data) error {
+	output.Stdout(tl.todoTxt())
+	return nil
+}
+
+// todoTxt returns the list in the todo.txt format. Primary items are tasks
+// without a project. Each sub-item is a task whose description is its path
+// below its primary item and whose project is its primary item.
+func (tl *List) todoTxt() string {
+	var b strings.Builder
+	for _, p := range orderedKeys(tl.Items, tl.Order) {
+		item := tl.Items[p]
+		b.WriteString(todoTxtLine(p, "", item) + "\n")
+		writeTodoTxtItems(&b, p, nil, item)
+	}
+	return b.String()
+}
+
+func writeTodoTxtItems(b *strings.Builder, project string, path []string, parent *Item) {
+	for _, k := range orderedKeys(parent.Items, parent.Order) {
+		item := parent.Items[k]
+		p := append(append([]string{}, path...), k)
+		b.WriteString(todoTxtLine(strings.Join(p, pathSeparator), project, item) + "\n")
+		writeTodoTxtItems(b, project, p, item)
+	}
+}
+
+// todoTxtLine returns the todo.txt task for an item. Completed items use
+// their last update as their completion date.
+func todoTxtLine(description, project string, item *Item) string {
+	var parts []string
+	if item.Done {
+		parts = append(parts, "x")
+		if !item.Updated.IsZero() {
+			parts = append(parts, item.Updated.Format(dateFormat))
+		}
+	} else if item.Priority != "" {
+		parts = append(parts, fmt.Sprintf("(%s)", todoTxtPriority(item.Priority)))
+	}
+	if !item.Created.IsZero() {
+		parts = append(parts, item.Created.Format(dateFormat))
+	}
+
+	parts = append(parts, description)
+	if project != "" {
+		parts = append(parts, "+"+todoTxtEscaper.Replace(project))
+	}
+	for _, t := range item.Tags {
+		if strings.HasPrefix(t, "@") && len(t) > 1 {
+			parts = append(parts, todoTxtEscaper.Replace(t))
+		} else {
+			parts = append(parts, todoTxtTagKey+todoTxtEscaper.Replace(t))
+		}
+	}
+	if !item.Due.IsZero() {
+		parts = append(parts, "due:"+item.Due.Format(dateFormat))
+	}
+	// Completed tasks keep their priority as a key-value pair.
+	if item.Done && item.Priority != "" {
+		parts = append(parts, "pri:"+todoTxtPriority(item.Priority))
+	}
+	return strings.Join(parts, " ")
+}
+
+// ImportTodoTxt merges the tasks in a todo.txt file into the list.
+func (tl *List) ImportTodoTxt(output command.Output, data *command.Data) error {
+	contents, err := readImportFile(data)
+	if err != nil {
+		return output.Stderrf("%v\n", err)
+	}
+	items, err := parseTodoTxt(contents, now().Location())
+	if err != nil {
+		return output.Stderrf("%v\n", err)
+	}
+	tl.mergeItems(items)
+	return nil
+}
+
+// parseTodoTxt parses the tasks in a todo.txt file. A task's first project is
+// its primary item and its description is the path below it (tasks without a
+// project are primary items). Contexts and "tag:" pairs are kept as tags.
+func parseTodoTxt(contents string, loc *time.Location) ([]*importedItem, error) {
+	var items []*importedItem
+	for i, line := range strings.Split(contents, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		item := &Item{}
+		if fields[0] == "x" {
+			item.Done = true
+			fields = fields[1:]
+			// The completion date isn't stored.
+			if len(fields) > 0 && isTodoTxtDate(fields[0], loc) {
+				fields = fields[1:]
+			}
+		}
+		if len(fields) > 0 {
+			if m := todoTxtPriorityRegex.FindStringSubmatch(fields[0]); m != nil {
+				item.Priority = parseTodoTxtPriority(m[1])
+				fields = fields[1:]
+			}
+		}
+		if len(fields) > 0 {
+			if t, err := time.ParseInLocation(dateFormat, fields[0], loc); err == nil {
+				item.Created = t
+				fields = fields[1:]
+			}
+		}
+
+		var project string
+		var description []string
+		for _, f := range fields {
+			switch {
+			case len(f) > 1 && strings.HasPrefix(f, "+"):
+				if project == "" {
+					project = todoTxtUnescaper.Replace(f[1:])
+				}
+			case len(f) > 1 && strings.HasPrefix(f, "@"):
+				item.Tags = append(item.Tags, todoTxtUnescaper.Replace(f))
+			case len(f) > len(todoTxtTagKey) && strings.HasPrefix(f, todoTxtTagKey):
+				item.Tags = append(item.Tags, todoTxtUnescaper.Replace(strings.TrimPrefix(f, todoTxtTagKey)))
+			case strings.HasPrefix(f, "due:"):
+				t, err := time.ParseInLocation(dateFormat, strings.TrimPrefix(f, "due:"), loc)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: invalid due date %q", i+1, f)
+				}
+				item.Due = t
+			case strings.HasPrefix(f, "pri:"):
+				item.Priority = parseTodoTxtPriority(strings.TrimPrefix(f, "pri:"))
+			default:
+				description = append(description, f)
+			}
+		}
+
+		var path []string
+		if project != "" {
+			path = append(path, project)
+		}
+		if len(description) > 0 {
+			d := strings.Join(description, " ")
+			if project == "" {
+				path = append(path, d)
+			} else {
+				path = append(path, strings.Split(d, pathSeparator)...)
+			}
+		}
+		if len(path) == 0 {
+			return nil, fmt.Errorf("line %d: task has no description", i+1)
+		}
+		items = append(items, &importedItem{
+			path: path,
+			item: item,
+		})
+	}
+	return items, nil
+}
+
+// isTodoTxtDate returns whether s is a todo.txt date.
+func isTodoTxtDate(s string, loc *time.Location) bool {
+	_, err := time.ParseInLocation(dateFormat, s, loc)
+	return err == nil
+}
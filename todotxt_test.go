@@ -0,0 +1,251 @@
+package todo
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leep-frog/command"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+var (
+	testCreated = time.Date(2023, time.January, 2, 0, 0, 0, 0, time.UTC)
+	testDue     = time.Date(2023, time.February, 10, 0, 0, 0, 0, time.UTC)
+)
+
+func TestExportTodoTxt(t *testing.T) {
+	l := &List{
+		Order: []string{"write", "sleep"},
+		Items: map[string]*Item{
+			"sleep": {
+				Priority: "P2",
+				Tags:     []string{"@home"},
+			},
+			"write": {
+				Items: map[string]*Item{
+					"code": {
+						Done:     true,
+						Priority: "P0",
+						Created:  testCreated,
+						Updated:  testTime,
+						Items: map[string]*Item{
+							"parser": {
+								Due:  testDue,
+								Tags: []string{"@desk", "laptop"},
+							},
+						},
+					},
+					"tests": {
+						Priority: "P1",
+						Created:  testCreated,
+					},
+				},
+			},
+		},
+	}
+
+	command.ExecuteTest(t, &command.ExecuteTestCase{
+		Node: l.Node(),
+		Args: []string{"export", "todotxt"},
+		WantStdout: strings.Join([]string{
+			"write",
+			"x 2023-02-03 2023-01-02 code +write pri:A",
+			"code/parser +write @desk tag:laptop due:2023-02-10",
+			"(B) 2023-01-02 tests +write",
+			"(C) sleep @home",
+			"",
+		}, "\n"),
+	})
+}
+
+func TestImportTodoTxt(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		l          *List
+		contents   []string
+		want       *List
+		wantStderr string
+	}{
+		{
+			name: "imports tasks",
+			contents: []string{
+				"x 2023-02-03 2023-01-02 code +write pri:A",
+				"code/parser +write @desk due:2023-02-10",
+				"",
+				"(B) 2023-01-02 write tests +write +other",
+				"(Z) sleep @home",
+			},
+			want: &List{
+				changed: true,
+				Order:   []string{"write", "sleep"},
+				Items: map[string]*Item{
+					"sleep": {
+						Created: testTime,
+						Updated: testTime,
+						Tags:    []string{"@home"},
+					},
+					"write": {
+						Created: testTime,
+						Updated: testTime,
+						Order:   []string{"code", "write tests"},
+						Items: map[string]*Item{
+							"code": {
+								Done:     true,
+								Priority: "P0",
+								Created:  testCreated,
+								Updated:  testTime,
+								Order:    []string{"parser"},
+								Items: map[string]*Item{
+									"parser": {
+										Created: testTime,
+										Updated: testTime,
+										Due:     testDue,
+										Tags:    []string{"@desk"},
+									},
+								},
+							},
+							"write tests": {
+								Priority: "P1",
+								Created:  testCreated,
+								Updated:  testTime,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "merges into existing items",
+			l: &List{
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Tags: []string{"@desk"},
+							},
+						},
+					},
+				},
+			},
+			contents: []string{
+				"x (A) code +write @laptop",
+			},
+			want: &List{
+				changed: true,
+				Items: map[string]*Item{
+					"write": {
+						Items: map[string]*Item{
+							"code": {
+								Done:     true,
+								Priority: "P0",
+								Updated:  testTime,
+								Tags:     []string{"@desk", "@laptop"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "errors on invalid due date",
+			contents: []string{
+				"sleep",
+				"code +write due:soon",
+			},
+			wantStderr: "line 2: invalid due date \"due:soon\"\n",
+		},
+		{
+			name: "errors on task without description",
+			contents: []string{
+				"(A) 2023-01-02 @home",
+			},
+			wantStderr: "line 1: task has no description\n",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			stubNow(t, testTime)
+			if test.l == nil {
+				test.l = &List{}
+			}
+			f := writeImportFile(t, "todo.txt", strings.Join(test.contents, "\n"))
+
+			executeTest(t, test.l, []string{"import", "todotxt", f}, &command.Data{
+				Values: map[string]interface{}{
+					fileArg: f,
+				},
+			}, nil, test.wantStderr)
+			command.ChangeTest(t, test.want, test.l, cmp.AllowUnexported(List{}), ignoreHistory)
+		})
+	}
+}
+
+func TestTodoTxtRoundTrip(t *testing.T) {
+	stubNow(t, testTime)
+	want := &List{
+		Order: []string{"write", "sleep", "work stuff"},
+		Items: map[string]*Item{
+			"sleep": {
+				Created:  testCreated,
+				Updated:  testTime,
+				Priority: "P3",
+				Tags:     []string{"50%", "@home", "home"},
+			},
+			// Projects with whitespace are encoded.
+			"work stuff": {
+				Created: testCreated,
+				Updated: testTime,
+				Order:   []string{"email"},
+				Items: map[string]*Item{
+					"email": {
+						Created: testCreated,
+						Updated: testTime,
+					},
+				},
+			},
+			// Primary items keep their own fields when they have sub-items.
+			"write": {
+				Done:     true,
+				Priority: "P2",
+				Created:  testCreated,
+				Updated:  testTime,
+				Due:      testDue,
+				Tags:     []string{"@desk", "big project"},
+				Order:    []string{"tests", "code"},
+				Items: map[string]*Item{
+					"code": {
+						Done:     true,
+						Priority: "P1",
+						Created:  testCreated,
+						Updated:  testTime,
+						Order:    []string{"parser"},
+						Items: map[string]*Item{
+							"parser": {
+								Created: testCreated,
+								Updated: testTime,
+								Due:     testDue,
+								Tags:    []string{"@desk", "@laptop", "laptop"},
+							},
+						},
+					},
+					"tests": {
+						Created: testCreated,
+						Updated: testTime,
+					},
+				},
+			},
+		},
+	}
+
+	items, err := parseTodoTxt(want.todoTxt(), time.UTC)
+	if err != nil {
+		t.Fatalf("parseTodoTxt() returned error: %v", err)
+	}
+	got := &List{}
+	got.mergeItems(items)
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(List{})); diff != "" {
+		t.Errorf("todo.txt round trip produced diff (-want, +got):\n%s", diff)
+	}
+}
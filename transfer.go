@@ -0,0 +1,126 @@
+package todo
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/leep-frog/command"
+)
+
+const (
+	fileArg  = "file"
+	fileDesc = "File to import items from"
+
+	// pathSeparator separates the names of nested items in formats that
+	// represent an item with a single string.
+	pathSeparator = "/"
+)
+
+// readImportFile returns the contents of the file provided to an import
+// command.
+func readImportFile(data *command.Data) (string, error) {
+	b, err := os.ReadFile(data.String(fileArg))
+	if err != nil {
+		return "", fmt.Errorf("failed to read import file: %v", err)
+	}
+	return string(b), nil
+}
+
+// importedItem is an item parsed from an imported file.
+type importedItem struct {
+	path []string
+	// item contains the imported fields. If nil, the item is only created if
+	// it doesn't already exist.
+	item *Item
+}
+
+// mergeItems merges imported items into the list.
+func (tl *List) mergeItems(items []*importedItem) {
+	for _, ii := range items {
+		from := ii.item
+		if from == nil {
+			if _, err := tl.get(ii.path); err == nil {
+				continue
+			}
+			from = &Item{}
+		}
+		if tl.mergeItem(ii.path, from) {
+			tl.changed = true
+		}
+	}
+}
+
+// mergeItem merges an imported item into the list, creating any items in the
+// path that don't exist yet. Set fields of the imported item overwrite those of
+// an existing item and its tags are added to the existing item's tags. Returns
+// whether the list was changed.
+func (tl *List) mergeItem(path []string, from *Item) bool {
+	if tl.Items == nil {
+		tl.Items = map[string]*Item{}
+	}
+
+	t := now()
+	items := tl.Items
+	var item *Item
+	for i, p := range path {
+		var ok bool
+		if item, ok = items[p]; !ok {
+			item = &Item{
+				Created: t,
+				Updated: t,
+			}
+			if i == len(path)-1 {
+				item.Done = from.Done
+				item.Note = from.Note
+				item.Due = from.Due
+				item.Priority = from.Priority
+				if len(from.Tags) > 0 {
+					item.Tags = append([]string{}, from.Tags...)
+					sort.Strings(item.Tags)
+				}
+				if !from.Created.IsZero() {
+					item.Created = from.Created
+				}
+			}
+			items[p] = item
+			tl.appendOrder(path[:i], p)
+			if i == len(path)-1 {
+				return true
+			}
+		}
+		if i < len(path)-1 && item.Items == nil {
+			item.Items = map[string]*Item{}
+		}
+		items = item.Items
+	}
+
+	changed := false
+	if item.Done != from.Done {
+		item.Done = from.Done
+		changed = true
+	}
+	if from.Note != "" && item.Note != from.Note {
+		item.Note = from.Note
+		changed = true
+	}
+	if !from.Due.IsZero() && !item.Due.Equal(from.Due) {
+		item.Due = from.Due
+		changed = true
+	}
+	if from.Priority != "" && item.Priority != from.Priority {
+		item.Priority = from.Priority
+		changed = true
+	}
+	for _, tag := range from.Tags {
+		if !item.hasTag(tag) {
+			item.Tags = append(item.Tags, tag)
+			changed = true
+		}
+	}
+	if changed {
+		sort.Strings(item.Tags)
+		item.touch()
+	}
+	return changed
+}
@@ -0,0 +1,107 @@
+package todo
+
+import (
+	"github.com/leep-frog/command/color"
+)
+
+// repair normalizes a loaded list so that the rest of the package can rely on
+// its invariants. Nil maps and items are initialized, formats of primary items
+// that don't exist are dropped, and manual orders are limited to the existing
+// items (without duplicates). Archived items without an item or path are
+// dropped, as are logged events, timers and pomodoros without a path. Returns whether
+// anything was repaired.
+func (tl *List) repair() bool {
+	repaired := false
+	if tl.Items == nil {
+		tl.Items = map[string]*Item{}
+	}
+	if tl.PrimaryFormats == nil {
+		tl.PrimaryFormats = map[string]*color.Format{}
+	}
+
+	if repairItems(tl.Items) {
+		repaired = true
+	}
+	if o, ok := repairOrder(tl.Items, tl.Order); ok {
+		tl.Order = o
+		repaired = true
+	}
+
+	for p, f := range tl.PrimaryFormats {
+		if _, ok := tl.Items[p]; !ok || f == nil {
+			delete(tl.PrimaryFormats, p)
+			repaired = true
+		}
+	}
+
+	var archive []*ArchivedItem
+	for _, ai := range tl.Archive {
+		if ai != nil && ai.Item != nil && len(ai.Path) > 0 {
+			archive = append(archive, ai)
+		}
+	}
+	if len(archive) != len(tl.Archive) {
+		tl.Archive = archive
+		repaired = true
+	}
+	for _, ai := range tl.Archive {
+		if repairItems(ai.Item.Items) {
+			repaired = true
+		}
+	}
+
+	var log []*Event
+	for _, e := range tl.Log {
+		if e != nil && len(e.Path) > 0 {
+			log = append(log, e)
+		}
+	}
+	if len(log) != len(tl.Log) {
+		tl.Log = log
+		repaired = true
+	}
+	if tl.Timer != nil && len(tl.Timer.Path) == 0 {
+		tl.Timer = nil
+		repaired = true
+	}
+	if tl.Pomodoro != nil && len(tl.Pomodoro.Path) == 0 {
+		tl.Pomodoro = nil
+		repaired = true
+	}
+	return repaired
+}
+
+// repairItems replaces nil items with empty ones and repairs the orders of
+// their sub-items.
+func repairItems(items map[string]*Item) bool {
+	repaired := false
+	for k, item := range items {
+		if item == nil {
+			items[k] = &Item{}
+			repaired = true
+			continue
+		}
+		if repairItems(item.Items) {
+			repaired = true
+		}
+		if o, ok := repairOrder(item.Items, item.Order); ok {
+			item.Order = o
+			repaired = true
+		}
+	}
+	return repaired
+}
+
+// repairOrder removes keys that aren't in items, as well as duplicate keys,
+// from order. Returns the repaired order and whether any keys were removed.
+func repairOrder(items map[string]*Item, order []string) ([]string, bool) {
+	seen := map[string]bool{}
+	var repaired []string
+	for _, k := range order {
+		if _, ok := items[k]; ok && !seen[k] {
+			repaired = append(repaired, k)
+			seen[k] = true
+		}
+	}
+	return repaired, len(repaired) != len(order)
+}